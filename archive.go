@@ -0,0 +1,382 @@
+package containers
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tarDirectory writes the contents of the local directory source as a tarball, with names relative to source
+// placed under prefix, and headers adjusted by attrs.
+func tarDirectory(w io.Writer, source, prefix string, attrs *fileAttrs) error {
+	tw := tar.NewWriter(w)
+	if err := writeDirs(tw, prefix, nil, attrs); err != nil {
+		return err
+	}
+
+	err := filepath.WalkDir(source, func(local string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, local)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(local); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(prefix, filepath.ToSlash(rel))
+		if err = writeHeader(tw, header, attrs); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(local)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("archiving directory `%s` failed with: %w", source, err)
+	}
+
+	return tw.Close()
+}
+
+// tarFS writes the contents of the file system as a tarball, with names placed under prefix, and headers adjusted by attrs.
+func tarFS(w io.Writer, fsys fs.FS, prefix string, attrs *fileAttrs) error {
+	tw := tar.NewWriter(w)
+	if err := writeDirs(tw, prefix, nil, attrs); err != nil {
+		return err
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || name == "." {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(prefix, name)
+		if err = writeHeader(tw, header, attrs); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("archiving file system failed with: %w", err)
+	}
+
+	return tw.Close()
+}
+
+// tarFiles writes the in-memory files as a tarball, with names placed under prefix, and headers adjusted by attrs.
+func tarFiles(w io.Writer, files map[string][]byte, prefix string, attrs *fileAttrs) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tar.NewWriter(w)
+	now := time.Now()
+	written := make(map[string]bool)
+	for _, name := range names {
+		if err := writeDirs(tw, path.Dir(path.Join(prefix, name)), written, attrs); err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     path.Join(prefix, name),
+			Size:     int64(len(files[name])),
+			Mode:     0644,
+			ModTime:  now,
+		}
+
+		if err := writeHeader(tw, header, attrs); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(files[name]); err != nil {
+			return fmt.Errorf("archiving `%s` failed with: %w", name, err)
+		}
+	}
+
+	return tw.Close()
+}
+
+// prefixTar copies the tarball, placing its entries under prefix.
+func prefixTar(w io.Writer, r io.Reader, prefix string) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	if err := writeDirs(tw, prefix, nil, nil); err != nil {
+		return err
+	}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(prefix, header.Name)
+		if header.Typeflag == tar.TypeLink {
+			header.Linkname = path.Join(prefix, header.Linkname)
+		}
+
+		if err = writeHeader(tw, header, nil); err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// writeDirs writes headers for the directory dir and its parents, skipping those in written, and recording them if not nil.
+// Directories default to mode 0755 owned by root, adjusted by attrs.
+func writeDirs(tw *tar.Writer, dir string, written map[string]bool, attrs *fileAttrs) error {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	if len(dir) == 0 || written[dir] {
+		return nil
+	}
+
+	if err := writeDirs(tw, path.Dir(dir), written, attrs); err != nil {
+		return err
+	}
+
+	if written != nil {
+		written[dir] = true
+	}
+
+	return writeHeader(tw, &tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     dir,
+		Mode:     0755,
+		ModTime:  time.Now(),
+	}, attrs)
+}
+
+// writeHeader writes the header with its name made relative to the root, applying attrs if set.
+func writeHeader(tw *tar.Writer, header *tar.Header, attrs *fileAttrs) error {
+	header.Name = strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+	if header.Typeflag == tar.TypeDir {
+		header.Name += "/"
+	}
+
+	if attrs != nil {
+		attrs.apply(header)
+	}
+
+	return tw.WriteHeader(header)
+}
+
+// untar extracts the tarball into the local directory dir, removing prefix from entry names.
+// Entries outside of prefix are skipped. The tarball is untrusted: entries may not write through symlinks,
+// and symlinks may not point outside of dir.
+func untar(r io.Reader, dir, prefix string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name, ok := stripPrefix(header.Name, prefix)
+		if !ok {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		mode := fs.FileMode(header.Mode).Perm()
+		if err = checkParents(dir, name); err == nil {
+			switch header.Typeflag {
+			case tar.TypeDir:
+				if err = checkNotSymlink(target); err == nil {
+					err = os.MkdirAll(target, mode|0700)
+				}
+			case tar.TypeReg:
+				err = untarFile(tr, dir, target, mode)
+			case tar.TypeSymlink:
+				err = untarSymlink(dir, name, header.Linkname)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("extracting `%s` failed with: %w", header.Name, err)
+		}
+	}
+}
+
+// checkParents returns an error if a parent of name inside dir is a symlink, which a write could be redirected through.
+func checkParents(dir, name string) error {
+	parent := dir
+	parts := strings.Split(name, "/")
+	for _, part := range parts[:len(parts)-1] {
+		parent = filepath.Join(parent, part)
+		if err := checkNotSymlink(parent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkNotSymlink returns an error if target exists and is a symlink.
+func checkNotSymlink(target string) error {
+	info, err := os.Lstat(target)
+	if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to write through symlink `%s`", target)
+	}
+
+	return nil
+}
+
+func untarFile(r io.Reader, dir, target string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if err := checkNotSymlink(target); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|oNoFollow, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(file, r); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// untarSymlink creates the symlink name inside dir, pointing to link which must be relative and resolve inside dir.
+func untarSymlink(dir, name, link string) error {
+	if path.IsAbs(link) || filepath.IsAbs(link) {
+		return fmt.Errorf("refusing absolute symlink target `%s`", link)
+	}
+
+	if resolved := path.Join(path.Dir(name), link); resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return fmt.Errorf("refusing symlink target `%s` outside of the destination", link)
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+		os.Remove(target)
+	}
+
+	return os.Symlink(link, target)
+}
+
+// stripPrefix returns name relative to prefix, cleaned so it cannot escape its root.
+func stripPrefix(name, prefix string) (string, bool) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	prefix = strings.Trim(path.Clean("/"+prefix), "/")
+	if len(prefix) > 0 {
+		if name != prefix && !strings.HasPrefix(name, prefix+"/") {
+			return "", false
+		}
+
+		name = strings.TrimPrefix(strings.TrimPrefix(name, prefix), "/")
+	}
+
+	return name, len(name) > 0
+}
+
+// rebaseTar copies the tarball, removing prefix from entry names and skipping entries outside of it.
+func rebaseTar(w io.Writer, r io.Reader, prefix string) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		name, ok := stripPrefix(header.Name, prefix)
+		if !ok {
+			continue
+		}
+
+		header.Name = name
+		if header.Typeflag == tar.TypeDir {
+			header.Name += "/"
+		}
+
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
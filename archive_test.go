@@ -0,0 +1,117 @@
+package containers
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type tarEntry struct {
+	header tar.Header
+	data   string
+}
+
+func craftTar(t *testing.T, entries ...tarEntry) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, entry := range entries {
+		entry.header.Size = int64(len(entry.data))
+		if err := tw.WriteHeader(&entry.header); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(entry.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf
+}
+
+func TestUntarRejectsEscapes(t *testing.T) {
+	outside := t.TempDir()
+	victim := filepath.Join(outside, "passwd")
+	if err := os.WriteFile(victim, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]tarEntry{
+		"absolute symlink": {
+			{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "a", Linkname: outside}},
+			{header: tar.Header{Typeflag: tar.TypeReg, Name: "a/passwd", Mode: 0644}, data: "owned"},
+		},
+		"relative symlink escaping": {
+			{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "sub/a", Linkname: "../../outside"}},
+		},
+		"write through symlink": {
+			{header: tar.Header{Typeflag: tar.TypeDir, Name: "real/", Mode: 0755}},
+			{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "a", Linkname: "real"}},
+			{header: tar.Header{Typeflag: tar.TypeReg, Name: "a/passwd", Mode: 0644}, data: "owned"},
+		},
+		"parent traversal": {
+			{header: tar.Header{Typeflag: tar.TypeReg, Name: "../../passwd", Mode: 0644}, data: "contained"},
+		},
+	}
+
+	for name, entries := range tests {
+		dir := t.TempDir()
+		err := untar(craftTar(t, entries...), dir, "")
+		if name == "parent traversal" {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			} else if data, _ := os.ReadFile(filepath.Join(dir, "passwd")); string(data) != "contained" {
+				t.Errorf("%s: expected the entry to be extracted inside the destination", name)
+			}
+		} else if err == nil {
+			t.Errorf("%s: expected extraction to fail", name)
+		}
+
+		if data, _ := os.ReadFile(victim); string(data) != "original" {
+			t.Fatalf("%s: file outside of the destination was overwritten", name)
+		}
+	}
+}
+
+func TestUntarExistingSymlink(t *testing.T) {
+	outside := t.TempDir()
+	victim := filepath.Join(outside, "passwd")
+	if err := os.WriteFile(victim, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.Symlink(victim, filepath.Join(dir, "passwd")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := untar(craftTar(t, tarEntry{header: tar.Header{Typeflag: tar.TypeReg, Name: "passwd", Mode: 0644}, data: "owned"}), dir, "")
+	if err == nil {
+		t.Error("Expected writing through an existing symlink to fail")
+	}
+
+	if data, _ := os.ReadFile(victim); string(data) != "original" {
+		t.Error("File outside of the destination was overwritten")
+	}
+}
+
+func TestUntarRelativeSymlink(t *testing.T) {
+	dir := t.TempDir()
+	err := untar(craftTar(t,
+		tarEntry{header: tar.Header{Typeflag: tar.TypeReg, Name: "bin/app", Mode: 0755}, data: "app"},
+		tarEntry{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "link/app", Linkname: "../bin/app"}},
+	), dir, "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "link", "app")); err != nil || string(data) != "app" {
+		t.Errorf("Expected symlink inside the destination to resolve got `%s`: %v", data, err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package containers
+
+import "syscall"
+
+// oNoFollow makes opening a file fail if it is a symlink.
+const oNoFollow = syscall.O_NOFOLLOW
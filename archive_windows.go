@@ -0,0 +1,4 @@
+package containers
+
+// oNoFollow is not supported on windows, where extracting relies on the symlink checks only.
+const oNoFollow = 0
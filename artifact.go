@@ -0,0 +1,130 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+)
+
+// Artifact is a file or directory collected from a container by Run, see CollectArtifacts.
+// It is spooled to a temporary file, removed by Remove or by closing the RunResult.
+type Artifact struct {
+	// Path is the path of the artifact inside the container.
+	Path string
+
+	file string
+	dir  bool
+}
+
+// Tar opens the artifact as a tarball, with entry names prefixed by the base name of its path.
+func (a *Artifact) Tar() (io.ReadCloser, error) {
+	return os.Open(a.file)
+}
+
+// Extract writes the artifact into the local directory dir: the contents of a directory, or the file itself.
+func (a *Artifact) Extract(dir string) error {
+	archive, err := a.Tar()
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	return untar(archive, dir, prefixOf(a.Path, a.dir))
+}
+
+// Remove deletes the spooled copy of the artifact.
+func (a *Artifact) Remove() error {
+	if err := os.Remove(a.file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// prefixOf returns the prefix of the entries copied out of srcPath that holds the contents of a directory.
+func prefixOf(srcPath string, dir bool) string {
+	if dir {
+		return path.Base(srcPath)
+	}
+
+	return ""
+}
+
+// CopyOut returns the file or directory at srcPath in the container as a tarball,
+// with entry names prefixed by the base name of srcPath. It works on stopped containers.
+func (c *Container) CopyOut(ctx context.Context, srcPath string) (io.ReadCloser, error) {
+	content, _, err := c.image.client.CopyFromContainer(ctx, c.id, srcPath)
+	if err != nil {
+		return nil, errorContainerCopyOut(c.id, srcPath, err)
+	}
+
+	return content, nil
+}
+
+// CopyOutDir writes the file or directory at srcPath in the container into the local directory dir:
+// the contents of a directory, or the file itself.
+func (c *Container) CopyOutDir(ctx context.Context, srcPath, dir string) error {
+	content, stat, err := c.image.client.CopyFromContainer(ctx, c.id, srcPath)
+	if err != nil {
+		return errorContainerCopyOut(c.id, srcPath, err)
+	}
+	defer content.Close()
+
+	if err = untar(content, dir, prefixOf(srcPath, stat.Mode.IsDir())); err != nil {
+		return errorContainerCopyOut(c.id, srcPath, err)
+	}
+
+	return nil
+}
+
+// collect spools the file or directory at srcPath in the container into an Artifact.
+func (c *Container) collect(ctx context.Context, srcPath string) (*Artifact, error) {
+	content, stat, err := c.image.client.CopyFromContainer(ctx, c.id, srcPath)
+	if err != nil {
+		return nil, errorContainerCopyOut(c.id, srcPath, err)
+	}
+	defer content.Close()
+
+	file, err := os.CreateTemp("", "container-artifact-*")
+	if err != nil {
+		return nil, errorContainerCopyOut(c.id, srcPath, err)
+	}
+
+	artifact := &Artifact{
+		Path: srcPath,
+		file: file.Name(),
+		dir:  stat.Mode.IsDir(),
+	}
+
+	_, err = io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		artifact.Remove()
+		return nil, errorContainerCopyOut(c.id, srcPath, err)
+	}
+
+	return artifact, nil
+}
+
+// collectArtifacts collects the paths set by CollectArtifacts, returning the artifacts found and the errors of the others.
+func (c *Container) collectArtifacts(ctx context.Context) ([]*Artifact, error) {
+	var (
+		artifacts []*Artifact
+		errs      []error
+	)
+	for _, srcPath := range c.artifacts {
+		artifact, err := c.collect(ctx, srcPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, errors.Join(errs...)
+}
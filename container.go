@@ -2,40 +2,221 @@ package containers
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"net"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
 )
 
-// Run starts the container and waits for the container to exit before returning the container logs.
-func (c *Container) Run(ctx context.Context) (*MuxedReadCloser, error) {
-	if err := c.image.client.ContainerStart(ctx, c.id, types.ContainerStartOptions{}); err != nil {
-		return nil, errorContainerStart(c.id, c.image.image, err)
+// Run starts the container and waits for the container to exit before returning the result, which holds the container logs.
+// It is the one-shot path over Start, Wait, and State; use those directly for long running services.
+// If the container exited with a non-zero code, or was killed for exceeding its memory limit, the result is returned with an *ExitError.
+// Paths set by CollectArtifacts are copied out of the container before it is removed, and returned with the result.
+// Unless KeepContainer is set, the container is always removed, even when ctx is cancelled or Run fails, and a failure to remove it is returned.
+func (c *Container) Run(ctx context.Context) (_ *RunResult, err error) {
+	if !c.keep {
+		defer func() {
+			err = errors.Join(err, c.Cleanup(ctx))
+		}()
 	}
 
-	if err := c.Wait(ctx); err != nil {
+	if err = c.Start(ctx); err != nil {
 		return nil, err
 	}
 
-	info, err := c.image.client.ContainerInspect(ctx, c.id)
+	if err = c.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	var streamErr error
+	if c.streamed != nil {
+		select {
+		case streamErr = <-c.streamed:
+		case <-ctx.Done():
+			return nil, errorContainerLogs(c.id, c.image.image, ctx.Err())
+		}
+	}
+
+	state, err := c.State(ctx)
 	if err != nil {
-		return nil, errorContainerInspect(c.id, c.image.image, err)
+		return nil, err
+	}
+
+	result := &RunResult{
+		ContainerID: c.id,
+		ExitCode:    state.ExitCode,
+		OOMKilled:   state.OOMKilled,
+		StartedAt:   state.StartedAt,
+		FinishedAt:  state.FinishedAt,
+	}
+	if !result.StartedAt.IsZero() && result.FinishedAt.After(result.StartedAt) {
+		result.Duration = result.FinishedAt.Sub(result.StartedAt)
 	}
 
 	var RetCodeErr error
-	if info.ContainerJSONBase.State.ExitCode != 0 {
-		RetCodeErr = errorContainerExitCode(c.id, c.image.image, info.ContainerJSONBase.State.ExitCode)
+	if state.OOMKilled {
+		RetCodeErr = errorContainerOOMKilled(c.id, c.image.image, state.ExitCode)
+	} else if state.ExitCode != 0 {
+		RetCodeErr = errorContainerExitCode(c.id, c.image.image, state.ExitCode)
+	}
+
+	RetCodeErr = errors.Join(RetCodeErr, streamErr)
+
+	if result.MuxedReadCloser, err = c.Logs(ctx); err != nil {
+		return nil, err
+	}
+
+	if result.Artifacts, err = c.collectArtifacts(ctx); err != nil {
+		RetCodeErr = errors.Join(RetCodeErr, err)
+	}
+
+	return result, RetCodeErr
+}
+
+// Commit creates a new image from the container's filesystem, tagged with the given reference.
+// The container must not have been removed yet; use the KeepContainer option so Run leaves it in place.
+func (c *Container) Commit(ctx context.Context, ref string, options ...CommitOption) (*Image, error) {
+	var imageRef *Reference
+	if len(ref) > 0 {
+		var err error
+		if imageRef, err = ParseReference(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	commitOptions := types.ContainerCommitOptions{Reference: ref}
+	for _, opt := range options {
+		if err := opt(&commitOptions); err != nil {
+			return nil, errorCommitOptions(c.id, c.image.image, err)
+		}
+	}
+
+	resp, err := c.image.client.ContainerCommit(ctx, c.id, commitOptions)
+	if err != nil {
+		return nil, errorContainerCommit(c.id, c.image.image, err)
+	}
+
+	name := ref
+	if imageRef == nil {
+		name = resp.ID
+		if imageRef, err = ParseReference(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Image{
+		client: c.image.client,
+		image:  name,
+		ref:    imageRef,
+	}, nil
+}
+
+// HostPort returns the host address, as `host:port`, the given container port is published on.
+// The container must be running; ports published on all interfaces are reported on the docker host's address.
+func (c *Container) HostPort(ctx context.Context, port string) (string, error) {
+	proto, number := nat.SplitProtoPort(port)
+	containerPort, err := nat.NewPort(proto, number)
+	if err != nil {
+		return "", errorContainerPort(c.id, port, err)
 	}
 
-	muxed, err := c.image.client.ContainerLogs(ctx, c.id, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	info, err := c.image.client.ContainerInspect(ctx, c.id)
+	if err != nil {
+		return "", errorContainerInspect(c.id, c.image.image, err)
+	}
+
+	if info.NetworkSettings == nil || len(info.NetworkSettings.Ports[containerPort]) == 0 {
+		return "", errorContainerPort(c.id, port, ErrorPortNotPublished)
+	}
+
+	bindings := info.NetworkSettings.Ports[containerPort]
+	binding := bindings[0]
+	for _, b := range bindings {
+		if ip := net.ParseIP(b.HostIP); ip == nil || ip.To4() != nil {
+			binding = b
+			break
+		}
+	}
+
+	host := binding.HostIP
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = c.image.client.daemonHostname()
+	}
+
+	return net.JoinHostPort(host, binding.HostPort), nil
+}
+
+// Container looks up an existing container by name or id, and returns a handle to it.
+// The handle does not own the container: Run leaves it in place as with KeepContainer, and Cleanup must be called explicitly.
+func (c *Client) Container(ctx context.Context, nameOrID string) (*Container, error) {
+	info, err := c.ContainerInspect(ctx, nameOrID)
+	if err != nil {
+		return nil, errorContainerLookup(nameOrID, err)
+	}
+
+	if info.ContainerJSONBase == nil || info.Config == nil {
+		return nil, errorContainerLookup(nameOrID, errors.New("missing container configuration"))
+	}
+
+	imageName := info.Config.Image
+	ref, err := ParseReference(imageName)
 	if err != nil {
-		return nil, errorContainerLogs(c.id, c.image.image, err)
+		imageName = info.Image
+		if ref, err = ParseReference(imageName); err != nil {
+			return nil, errorContainerLookup(nameOrID, err)
+		}
+	}
+
+	cont := &Container{
+		image: &Image{
+			client: c,
+			image:  imageName,
+			ref:    ref,
+		},
+		id:      info.ID,
+		name:    strings.TrimPrefix(info.Name, "/"),
+		cmd:     info.Config.Cmd,
+		tty:     info.Config.Tty,
+		shell:   info.Config.Shell,
+		env:     info.Config.Env,
+		workDir: info.Config.WorkingDir,
+		config:  *info.Config,
+		keep:    true,
 	}
+	if info.HostConfig != nil {
+		cont.hostConfig = *info.HostConfig
+		cont.mounts = info.HostConfig.Mounts
+	}
+
+	return cont, nil
+}
+
+// Resize sets the size of the container's TTY, in columns and rows.
+func (c *Container) Resize(ctx context.Context, cols, rows uint) error {
+	if err := c.image.client.ContainerResize(ctx, c.id, types.ResizeOptions{Width: cols, Height: rows}); err != nil {
+		return errorContainerResize(c.id, c.image.image, err)
+	}
+
+	return nil
+}
 
-	c.Cleanup(ctx)
+// ID returns the id of the container.
+func (c *Container) ID() string {
+	return c.id
+}
+
+// Name returns the name of the container, as set by the Name option or found by Client.Container.
+func (c *Container) Name() string {
+	return c.name
+}
 
-	return &MuxedReadCloser{reader: muxed}, RetCodeErr
+// Image returns the image of the container.
+func (c *Container) Image() *Image {
+	return c.image
 }
 
 // Wait calls the ContainerWait method for the container, and returns once a response has been received.
@@ -52,10 +233,21 @@ func (c *Container) Wait(ctx context.Context) error {
 	return nil
 }
 
-// Cleanup removes the container from the docker host client.
+// Cleanup kills and removes the container, along with its anonymous volumes, from the docker host client.
+// It runs detached from the cancellation of ctx, bounded by CleanupTimeout, so it can be used once ctx is done.
+// A container that was already removed is not an error.
 func (c *Container) Cleanup(ctx context.Context) error {
-	if err := c.image.client.ContainerRemove(ctx, c.id, types.ContainerRemoveOptions{}); err != nil {
-		return fmt.Errorf("removing container with id `%s` failed with: %s", c.id, err)
+	if c.release != nil {
+		c.release()
 	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CleanupTimeout)
+	defer cancel()
+
+	err := c.image.client.ContainerRemove(ctx, c.id, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+	if err != nil && !client.IsErrNotFound(err) {
+		return errorContainerRemove(c.id, c.image.image, err)
+	}
+
 	return nil
 }
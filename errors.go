@@ -7,6 +7,27 @@ import (
 
 var errorBasicFormat = "%s with: %w"
 
+// ErrorInvalidReference is matched by any *ReferenceError.
+var ErrorInvalidReference = errors.New("invalid image reference")
+
+// ReferenceError is returned when an image reference cannot be parsed.
+type ReferenceError struct {
+	Reference string
+	Err       error
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("%s `%s` with: %s", ErrorInvalidReference, e.Reference, e.Err)
+}
+
+func (e *ReferenceError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ReferenceError) Is(target error) bool {
+	return target == ErrorInvalidReference
+}
+
 // Image Method Errors
 var (
 	ErrorImageOptions         = errors.New("image options failed")
@@ -16,9 +37,16 @@ var (
 	ErrorImageBuildDockerFile = errors.New("building Dockerfile failed")
 	ErrorImageBuildResCopy    = errors.New("copying response from image build failed")
 	ErrorImagePullStatus      = errors.New("copying pull status failed")
+	ErrorImageLoad            = errors.New("loading image failed")
+	ErrorClientLoad           = errors.New("client load failed")
+	ErrorImageLoadStatus      = errors.New("reading load status failed")
+	ErrorImageExport          = errors.New("exporting image failed")
+	ErrorClientSave           = errors.New("client save failed")
+	ErrorImageTag             = errors.New("tagging image failed")
 
 	ErrorContainerOptions = errors.New("container options failed")
 	ErrorContainerCreate  = errors.New("creating container failed")
+	ErrorContainerMount   = errors.New("preparing container mounts failed")
 
 	errorImageFormat = "%s for image `%s` with: %w"
 )
@@ -51,10 +79,38 @@ func errorImagePullStatus(err error) error {
 	return fmt.Errorf(errorBasicFormat, ErrorImagePullStatus, err)
 }
 
+func errorImageLoad(image string, err error) error {
+	return fmt.Errorf(errorImageFormat, ErrorImageLoad, image, err)
+}
+
+func errorClientLoad(err error) error {
+	return fmt.Errorf(errorBasicFormat, ErrorClientLoad, err)
+}
+
+func errorImageLoadStatus(err error) error {
+	return fmt.Errorf(errorBasicFormat, ErrorImageLoadStatus, err)
+}
+
+func errorImageExport(image string, err error) error {
+	return fmt.Errorf(errorImageFormat, ErrorImageExport, image, err)
+}
+
+func errorClientSave(err error) error {
+	return fmt.Errorf(errorBasicFormat, ErrorClientSave, err)
+}
+
+func errorImageTag(image string, err error) error {
+	return fmt.Errorf(errorImageFormat, ErrorImageTag, image, err)
+}
+
 func errorContainerOptions(image string, err error) error {
 	return fmt.Errorf(errorImageFormat, ErrorContainerOptions, image, err)
 }
 
+func errorContainerMount(image string, err error) error {
+	return fmt.Errorf(errorImageFormat, ErrorContainerMount, image, err)
+}
+
 func errorContainerCreate(image string, err error) error {
 	return fmt.Errorf(errorImageFormat, ErrorContainerCreate, image, err)
 }
@@ -62,11 +118,32 @@ func errorContainerCreate(image string, err error) error {
 // Container Method Errors
 var (
 	ErrorContainerStart   = errors.New("start container failed")
+	ErrorContainerAttach  = errors.New("attaching to container failed")
 	ErrorContainerWait    = errors.New("container wait failed")
 	ErrorClientWait       = errors.New("client wait failed")
 	ErrorContainerInspect = errors.New("inspecting container failed")
 	ErrorExitCode         = errors.New("exit-code")
+	ErrorOOMKilled        = errors.New("out of memory killed")
 	ErrorContainerLogs    = errors.New("getting container logs failed")
+	ErrorLogOptions       = errors.New("log options failed")
+	ErrorContainerRemove  = errors.New("removing container failed")
+	ErrorContainerStop    = errors.New("stopping container failed")
+	ErrorContainerKill    = errors.New("killing container failed")
+	ErrorContainerRestart = errors.New("restarting container failed")
+	ErrorContainerPause   = errors.New("pausing container failed")
+	ErrorContainerUnpause = errors.New("unpausing container failed")
+	ErrorCommitOptions    = errors.New("commit options failed")
+	ErrorContainerCommit  = errors.New("committing container failed")
+	ErrorContainerPort    = errors.New("getting container host port failed")
+	ErrorContainerLookup  = errors.New("looking up container failed")
+	ErrorContainerResize  = errors.New("resizing container failed")
+	ErrorExecOptions      = errors.New("exec options failed")
+	ErrorContainerExec    = errors.New("executing command in container failed")
+	ErrorContainerCopyIn  = errors.New("copying files into container failed")
+	ErrorContainerCopyOut = errors.New("copying files out of container failed")
+
+	ErrorNilSource        = errors.New("source is nil")
+	ErrorPortNotPublished = errors.New("port is not published")
 
 	errorContainerFormat = "%s for container Id:`%s` image:`%s` with: %w"
 )
@@ -75,6 +152,10 @@ func errorContainerStart(id, image string, err error) error {
 	return fmt.Errorf(errorContainerFormat, ErrorContainerStart, id, image, err)
 }
 
+func errorContainerAttach(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerAttach, id, image, err)
+}
+
 func errorContainerWait(id, image string, err error) error {
 	return fmt.Errorf(errorContainerFormat, ErrorContainerWait, id, image, err)
 }
@@ -83,10 +164,188 @@ func errorContainerInspect(id, image string, err error) error {
 	return fmt.Errorf(errorContainerFormat, ErrorContainerInspect, id, image, err)
 }
 
+// ExitError is returned when a container or exec exits with a non-zero code, or is killed for exceeding its memory limit.
+// It matches ErrorExitCode, and ErrorOOMKilled when OOMKilled is set.
+type ExitError struct {
+	ContainerID string
+	Image       string
+	ExitCode    int
+	OOMKilled   bool
+}
+
+func (e *ExitError) Error() string {
+	if e.OOMKilled {
+		return fmt.Sprintf("container Id:`%s` image:`%s` was %s with %s:%d", e.ContainerID, e.Image, ErrorOOMKilled, ErrorExitCode, e.ExitCode)
+	}
+
+	return fmt.Sprintf("container Id:`%s` image:`%s` failed with %s:%d", e.ContainerID, e.Image, ErrorExitCode, e.ExitCode)
+}
+
+func (e *ExitError) Is(target error) bool {
+	return target == ErrorExitCode || (e.OOMKilled && target == ErrorOOMKilled)
+}
+
 func errorContainerExitCode(id, image string, code int) error {
-	return fmt.Errorf("container Id:`%s` image:`%s` failed with %w:%d", id, image, ErrorExitCode, code)
+	return &ExitError{ContainerID: id, Image: image, ExitCode: code}
+}
+
+func errorContainerOOMKilled(id, image string, code int) error {
+	return &ExitError{ContainerID: id, Image: image, ExitCode: code, OOMKilled: true}
+}
+
+func errorLogOptions(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorLogOptions, id, image, err)
 }
 
 func errorContainerLogs(id, image string, err error) error {
 	return fmt.Errorf(errorContainerFormat, ErrorContainerLogs, id, image, err)
 }
+
+func errorContainerPort(id, port string, err error) error {
+	return fmt.Errorf("%s for container Id:`%s` port:`%s` with: %w", ErrorContainerPort, id, port, err)
+}
+
+func errorContainerLookup(nameOrID string, err error) error {
+	return fmt.Errorf("%s for container `%s` with: %w", ErrorContainerLookup, nameOrID, err)
+}
+
+func errorContainerResize(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerResize, id, image, err)
+}
+
+func errorExecOptions(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorExecOptions, id, image, err)
+}
+
+func errorContainerExec(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerExec, id, image, err)
+}
+
+func errorContainerCopyIn(id, dst string, err error) error {
+	return fmt.Errorf("%s for container Id:`%s` destination:`%s` with: %w", ErrorContainerCopyIn, id, dst, err)
+}
+
+func errorContainerCopyOut(id, src string, err error) error {
+	return fmt.Errorf("%s for container Id:`%s` source:`%s` with: %w", ErrorContainerCopyOut, id, src, err)
+}
+
+func errorContainerRemove(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerRemove, id, image, err)
+}
+
+func errorContainerStop(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerStop, id, image, err)
+}
+
+func errorContainerKill(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerKill, id, image, err)
+}
+
+func errorContainerRestart(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerRestart, id, image, err)
+}
+
+func errorContainerPause(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerPause, id, image, err)
+}
+
+func errorContainerUnpause(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerUnpause, id, image, err)
+}
+
+// Wait Strategy and Health Errors
+var (
+	ErrorContainerHealth   = errors.New("getting container health failed")
+	ErrorContainerNotReady = errors.New("waiting for container to be ready failed")
+	ErrorContainerExited   = errors.New("container exited")
+	ErrorNoHealthCheck     = errors.New("container has no health check")
+	ErrorNoWaitStrategy    = errors.New("no wait strategy given")
+)
+
+func errorContainerHealth(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerHealth, id, image, err)
+}
+
+func errorContainerNotReady(id, image string, err error, logs string) error {
+	return fmt.Errorf(errorContainerFormat+"\nrecent logs:\n%s", ErrorContainerNotReady, id, image, err, logs)
+}
+
+func errorCommitOptions(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorCommitOptions, id, image, err)
+}
+
+func errorContainerCommit(id, image string, err error) error {
+	return fmt.Errorf(errorContainerFormat, ErrorContainerCommit, id, image, err)
+}
+
+// Network Method Errors
+var (
+	ErrorNetworkOptions = errors.New("network options failed")
+	ErrorNetworkCreate  = errors.New("creating network failed")
+	ErrorNetworkRemove  = errors.New("removing network failed")
+	ErrorNetworkExists  = errors.New("existing network does not match the options")
+
+	errorNetworkFormat = "%s for network `%s` with: %w"
+)
+
+func errorNetworkOptions(name string, err error) error {
+	return fmt.Errorf(errorNetworkFormat, ErrorNetworkOptions, name, err)
+}
+
+func errorNetworkCreate(name string, err error) error {
+	return fmt.Errorf(errorNetworkFormat, ErrorNetworkCreate, name, err)
+}
+
+func errorNetworkExists(name string, err error) error {
+	return fmt.Errorf(errorNetworkFormat, ErrorNetworkExists, name, err)
+}
+
+func errorNetworkRemove(name string, err error) error {
+	return fmt.Errorf(errorNetworkFormat, ErrorNetworkRemove, name, err)
+}
+
+// Volume Method Errors
+var (
+	ErrorVolumeOptions = errors.New("volume options failed")
+	ErrorVolumeCreate  = errors.New("creating volume failed")
+	ErrorVolumeList    = errors.New("listing volumes failed")
+	ErrorVolumeInspect = errors.New("inspecting volume failed")
+	ErrorVolumeRemove  = errors.New("removing volume failed")
+	ErrorVolumeImport  = errors.New("importing into volume failed")
+	ErrorVolumeExport  = errors.New("exporting from volume failed")
+	ErrorVolumeExists  = errors.New("existing volume does not match the options")
+
+	errorVolumeFormat = "%s for volume `%s` with: %w"
+)
+
+func errorVolumeOptions(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeOptions, name, err)
+}
+
+func errorVolumeCreate(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeCreate, name, err)
+}
+
+func errorVolumeList(err error) error {
+	return fmt.Errorf(errorBasicFormat, ErrorVolumeList, err)
+}
+
+func errorVolumeInspect(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeInspect, name, err)
+}
+
+func errorVolumeRemove(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeRemove, name, err)
+}
+
+func errorVolumeExists(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeExists, name, err)
+}
+
+func errorVolumeImport(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeImport, name, err)
+}
+
+func errorVolumeExport(name string, err error) error {
+	return fmt.Errorf(errorVolumeFormat, ErrorVolumeExport, name, err)
+}
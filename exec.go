@@ -0,0 +1,97 @@
+package containers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// execInspectInterval is the interval the exit code of a finished exec is polled at, until the docker host records it.
+var execInspectInterval = 50 * time.Millisecond
+
+// Exec runs the command inside the running container, and waits for it to exit.
+// If the command exited with a non-zero code the result is returned with an error.
+// Once ctx is done, Exec stops reading the output and returns; the docker host cannot stop the command itself,
+// which keeps running until it exits or the container stops.
+func (c *Container) Exec(ctx context.Context, cmd []string, options ...ExecOption) (*ExecResult, error) {
+	e := &execution{
+		config: types.ExecConfig{
+			Cmd:          cmd,
+			AttachStdout: true,
+			AttachStderr: true,
+		},
+	}
+
+	for _, opt := range options {
+		if err := opt(e); err != nil {
+			return nil, errorExecOptions(c.id, c.image.image, err)
+		}
+	}
+	e.config.AttachStdin = e.stdin != nil
+
+	created, err := c.image.client.ContainerExecCreate(ctx, c.id, e.config)
+	if err != nil {
+		return nil, errorContainerExec(c.id, c.image.image, err)
+	}
+
+	attached, err := c.image.client.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	if err != nil {
+		return nil, errorContainerExec(c.id, c.image.image, err)
+	}
+	defer attached.Close()
+
+	stop := context.AfterFunc(ctx, attached.Close)
+	defer stop()
+
+	if e.stdin != nil {
+		go func() {
+			io.Copy(attached.Conn, e.stdin)
+			attached.CloseWrite()
+		}()
+	}
+
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, attached.Reader)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, errorContainerExec(c.id, c.image.image, ctxErr)
+	}
+	if err != nil {
+		return nil, errorContainerExec(c.id, c.image.image, err)
+	}
+
+	info, err := c.execInspect(ctx, created.ID)
+	if err != nil {
+		return nil, errorContainerExec(c.id, c.image.image, err)
+	}
+
+	result := &ExecResult{
+		ExitCode: info.ExitCode,
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+	}
+	if info.ExitCode != 0 {
+		return result, errorContainerExitCode(c.id, c.image.image, info.ExitCode)
+	}
+
+	return result, nil
+}
+
+// execInspect inspects the exec until it is no longer running.
+func (c *Container) execInspect(ctx context.Context, id string) (types.ContainerExecInspect, error) {
+	for {
+		info, err := c.image.client.ContainerExecInspect(ctx, id)
+		if err != nil || !info.Running {
+			return info, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return info, ctx.Err()
+		case <-time.After(execInspectInterval):
+		}
+	}
+}
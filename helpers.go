@@ -1,6 +1,10 @@
 package containers
 
-import "github.com/docker/docker/api/types/filters"
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/filters"
+)
 
 // New Filter returns a filter argument to perform key value Lookups on docker host.
 func NewFilter(key, value string) filters.Args {
@@ -9,3 +13,14 @@ func NewFilter(key, value string) filters.Args {
 
 	return filter
 }
+
+// checkSubset returns an error naming the first entry of requested that existing does not hold, with the same value.
+func checkSubset(kind string, requested, existing map[string]string) error {
+	for key, value := range requested {
+		if current, ok := existing[key]; !ok || current != value {
+			return fmt.Errorf("%s `%s` is `%s`, expected `%s`", kind, key, current, value)
+		}
+	}
+
+	return nil
+}
@@ -4,23 +4,32 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/taubyte/go-simple-container/oci"
 )
 
 // Image initializes the given image, and attempts to pull the container from docker hub.
 // If the Build() Option is provided then the given DockerFile tarball is built and returned.
+// The name is parsed and normalized before any call to the docker host, a *ReferenceError is returned if it is invalid.
 func (c *Client) Image(ctx context.Context, name string, options ...ImageOption) (image *Image, err error) {
+	ref, err := ParseReference(name)
+	if err != nil {
+		return nil, err
+	}
+
 	image = &Image{
 		client: c,
 		image:  name,
+		ref:    ref,
 	}
 
 	for _, opt := range options {
@@ -30,10 +39,18 @@ func (c *Client) Image(ctx context.Context, name string, options ...ImageOption)
 	}
 
 	imageExists := image.checkImageExists(ctx)
-	if image.buildTarball != nil && (ForceRebuild || !imageExists) {
+	if image.assembly != nil || len(image.layout) > 0 {
+		if ForceRebuild || !imageExists {
+			if err := image.loadImage(ctx); err != nil {
+				return nil, errorImageLoad(name, err)
+			}
+			image.resolved = nil
+		}
+	} else if image.buildTarball != nil && (ForceRebuild || !imageExists) {
 		if err := image.buildImage(ctx); err != nil {
 			return nil, errorImageBuild(name, err)
 		}
+		image.resolved = nil
 	} else {
 		if image, err = image.Pull(ctx); err != nil {
 			err = errorImagePull(name, err)
@@ -48,13 +65,27 @@ func (c *Client) Image(ctx context.Context, name string, options ...ImageOption)
 	return
 }
 
-// checkImage checks the docker host client if the image is known.
+// checkImage checks the docker host client if the image is known, either by its reference or by one of its
+// rewritten or mirrored candidates. An image known only by a candidate is left untouched on the docker host,
+// the candidate is recorded as the resolved reference of the image.
 func (i *Image) checkImageExists(ctx context.Context) bool {
-	res, err := i.client.ImageList(ctx, types.ImageListOptions{
-		Filters: NewFilter("reference", i.image),
-	})
+	if i.client.hasImage(ctx, i.ref) {
+		return true
+	}
+
+	candidates, err := i.client.candidates(i.ref)
+	if err != nil {
+		return false
+	}
+
+	for _, candidate := range candidates {
+		if candidate.String() != i.ref.String() && i.client.hasImage(ctx, candidate) {
+			i.resolved = candidate
+			return true
+		}
+	}
 
-	return err == nil && len(res) > 0
+	return false
 }
 
 // buildImage builds a DockerFile tarball as a docker image.
@@ -65,7 +96,7 @@ func (i *Image) buildImage(ctx context.Context) error {
 		types.ImageBuildOptions{
 			Context:    i.buildTarball,
 			Dockerfile: "Dockerfile",
-			Tags:       []string{i.image},
+			Tags:       []string{i.ref.String()},
 			Remove:     true,
 		},
 	)
@@ -81,11 +112,83 @@ func (i *Image) buildImage(ctx context.Context) error {
 	return nil
 }
 
+// loadImage writes the assembled, or OCI layout, image through the docker load API, tagged with the image name.
+func (i *Image) loadImage(ctx context.Context) error {
+	assembly := i.assembly
+	if assembly == nil {
+		layout, err := oci.New(oci.Layout(i.layout))
+		if err != nil {
+			return err
+		}
+		defer layout.Close()
+
+		assembly = layout
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(assembly.Write(writer, i.ref.String()))
+	}()
+	defer reader.Close()
+
+	res, err := i.client.ImageLoad(ctx, reader, true)
+	if err != nil {
+		return errorClientLoad(err)
+	}
+	defer res.Body.Close()
+
+	decoder := json.NewDecoder(res.Body)
+	for {
+		var message jsonmessage.JSONMessage
+		if err = decoder.Decode(&message); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errorImageLoadStatus(err)
+		}
+
+		if message.Error != nil {
+			return errorClientLoad(message.Error)
+		}
+	}
+}
+
+// ExportOCI writes the image as an OCI image layout in dir, converted from the docker save stream.
+func (i *Image) ExportOCI(ctx context.Context, dir string) error {
+	reader, err := i.client.ImageSave(ctx, []string{i.local().String()})
+	if err != nil {
+		return errorImageExport(i.image, errorClientSave(err))
+	}
+	defer reader.Close()
+
+	layout, err := oci.New(oci.Base(reader))
+	if err != nil {
+		return errorImageExport(i.image, err)
+	}
+	defer layout.Close()
+
+	var tags []string
+	if !i.ref.isID() {
+		tags = append(tags, i.ref.String())
+	}
+
+	if err = layout.WriteLayout(dir, tags...); err != nil {
+		return errorImageExport(i.image, err)
+	}
+
+	return nil
+}
+
 // Pull retrieves latest changes to the image from docker hub.
+// The client's rewrite rules and registry mirrors are applied, the pulled image is tagged with the image reference.
 func (i *Image) Pull(ctx context.Context) (*Image, error) {
-	reader, err := i.client.ImagePull(ctx, i.image, types.ImagePullOptions{})
+	return i, i.pullCandidates(ctx)
+}
+
+// pull retrieves the image from the given reference.
+func (i *Image) pull(ctx context.Context, ref *Reference) error {
+	reader, err := i.client.ImagePull(ctx, ref.String(), types.ImagePullOptions{})
 	if err != nil {
-		return i, errorClientPull(err)
+		return errorClientPull(err)
 	}
 	defer reader.Close()
 
@@ -99,15 +202,20 @@ func (i *Image) Pull(ctx context.Context) (*Image, error) {
 				Current int
 				Total   int
 			}
-			Id string
+			Id    string
+			Error string
 		}
 
 		if err = json.Unmarshal(line, &status); err != nil {
-			return i, errorImagePullStatus(err)
+			return errorImagePullStatus(err)
+		}
+
+		if len(status.Error) > 0 {
+			return errorClientPull(errors.New(status.Error))
 		}
 	}
 
-	return i, nil
+	return nil
 }
 
 // Instantiate sets given options and creates the container from the docker image.
@@ -122,32 +230,53 @@ func (i *Image) Instantiate(ctx context.Context, options ...ContainerOption) (*C
 		}
 	}
 
-	mounts := make([]mount.Mount, len(c.volumes))
-	for idx, volume := range c.volumes {
-		mounts[idx] = mount.Mount{
-			Type:   mount.TypeBind,
-			Source: volume.source,
-			Target: volume.target,
-		}
+	if err := c.prepareMounts(ctx); err != nil {
+		return nil, errorContainerMount(i.image, err)
 	}
 
-	config := &container.Config{
-		Image: i.image,
-		Cmd:   c.cmd,
-		Shell: c.shell,
-		Tty:   false,
-		Env:   c.env,
+	config := c.config
+	config.Image = i.local().String()
+	config.Cmd = c.cmd
+	config.Shell = c.shell
+	config.Tty = c.tty
+	config.Env = c.env
+	if c.stdin != nil {
+		config.OpenStdin = true
+		config.AttachStdin = true
+		config.StdinOnce = true
 	}
 	if len(c.workDir) > 0 {
 		config.WorkingDir = c.workDir
 	}
 
-	resp, err := c.image.client.ContainerCreate(ctx, config, &container.HostConfig{Mounts: mounts}, nil, nil, "")
+	hostConfig := c.hostConfig
+	hostConfig.Mounts = c.mounts
+
+	var networkConfig *network.NetworkingConfig
+	if len(c.aliases) > 0 {
+		if !hostConfig.NetworkMode.IsUserDefined() {
+			return nil, errorContainerOptions(i.image, errors.New("network aliases require a named network"))
+		}
+
+		networkConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				hostConfig.NetworkMode.NetworkName(): {Aliases: c.aliases},
+			},
+		}
+	}
+
+	resp, err := c.image.client.ContainerCreate(ctx, &config, &hostConfig, networkConfig, nil, c.name)
 	if err != nil {
 		return nil, errorContainerCreate(c.image.Name(), err)
 	}
 	c.id = resp.ID
 
+	for _, files := range c.files {
+		if err = c.CopyIn(ctx, files.dst, files.src); err != nil {
+			return nil, errors.Join(err, c.Cleanup(ctx))
+		}
+	}
+
 	return c, nil
 }
 
@@ -175,7 +304,21 @@ func (c *Client) Clean(ctx context.Context, age time.Duration, filter filters.Ar
 	return err
 }
 
-// Name returns the name of the image
+// Name returns the name the image was requested with.
 func (i *Image) Name() string {
 	return i.image
 }
+
+// local returns the reference the image is known by on the docker host.
+func (i *Image) local() *Reference {
+	if i.resolved != nil {
+		return i.resolved
+	}
+
+	return i.ref
+}
+
+// Reference returns the parsed and normalized reference of the image.
+func (i *Image) Reference() *Reference {
+	return i.ref
+}
@@ -0,0 +1,269 @@
+package containers
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// State is the state of a container as reported by the docker host.
+type State struct {
+	// Status is one of created, running, paused, restarting, removing, exited, or dead.
+	Status     string
+	Running    bool
+	Paused     bool
+	Restarting bool
+	OOMKilled  bool
+	Dead       bool
+	Pid        int
+	ExitCode   int
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Start starts the container without waiting for it to exit, for long running services.
+// The Stdin reader, if set, is streamed into the container once started the first time,
+// and the output is copied in real time to the writers set by StreamOutput, on every start.
+func (c *Container) Start(ctx context.Context) error {
+	var stdin, output *types.HijackedResponse
+	if c.stdin != nil && !c.stdinStreamed {
+		resp, err := c.image.client.ContainerAttach(ctx, c.id, types.ContainerAttachOptions{Stream: true, Stdin: true})
+		if err != nil {
+			return errorContainerAttach(c.id, c.image.image, err)
+		}
+
+		stdin = &resp
+	}
+
+	if c.stdout != nil || c.stderr != nil {
+		resp, err := c.image.client.ContainerAttach(ctx, c.id, types.ContainerAttachOptions{
+			Stream: true,
+			Stdout: true,
+			Stderr: true,
+		})
+		if err != nil {
+			if stdin != nil {
+				stdin.Close()
+			}
+			return errorContainerAttach(c.id, c.image.image, err)
+		}
+
+		output = &resp
+	}
+
+	if err := c.image.client.ContainerStart(ctx, c.id, types.ContainerStartOptions{}); err != nil {
+		for _, attached := range []*types.HijackedResponse{stdin, output} {
+			if attached != nil {
+				attached.Close()
+			}
+		}
+		return errorContainerStart(c.id, c.image.image, err)
+	}
+
+	if stdin != nil {
+		c.stdinStreamed = true
+		c.watchStdin(ctx, stdin)
+		go c.streamStdin(stdin)
+	}
+
+	if output != nil {
+		streamed := make(chan error, 1)
+		c.streamed = streamed
+		go func() {
+			defer output.Close()
+			streamed <- c.streamOutput(output.Reader)
+		}()
+	}
+
+	return nil
+}
+
+// watchStdin closes the connection attached for the Stdin reader once the container stops, or is cleaned up.
+// The reader itself is owned by the caller and is left open, streaming it ends on the first write after the close.
+func (c *Container) watchStdin(ctx context.Context, attached *types.HijackedResponse) {
+	var once sync.Once
+	release := func() {
+		once.Do(attached.Close)
+	}
+	c.release = release
+
+	go func() {
+		statusCh, errCh := c.image.client.ContainerWait(context.WithoutCancel(ctx), c.id, container.WaitConditionNotRunning)
+		select {
+		case <-statusCh:
+		case <-errCh:
+		}
+		release()
+	}()
+}
+
+// streamStdin copies the Stdin reader into the attached container, closing the container's stdin once the reader reaches EOF.
+func (c *Container) streamStdin(attached *types.HijackedResponse) {
+	io.Copy(attached.Conn, c.stdin)
+	attached.CloseWrite()
+}
+
+// streamOutput copies the attached output into the writers set by StreamOutput, until the container exits.
+func (c *Container) streamOutput(reader io.Reader) error {
+	stdOut, stdErr := c.stdout, c.stderr
+	if stdOut == nil {
+		stdOut = io.Discard
+	}
+	if stdErr == nil {
+		stdErr = io.Discard
+	}
+
+	mx := &MuxedReadCloser{reader: io.NopCloser(reader), tty: c.tty}
+	if _, err := mx.copy(stdOut, stdErr); err != nil {
+		return errorContainerLogs(c.id, c.image.image, err)
+	}
+
+	return nil
+}
+
+// Stop sends the stop signal to the container, and kills it if it is still running once grace has elapsed.
+// A negative grace uses the stop timeout of the container, which defaults to 10 seconds.
+func (c *Container) Stop(ctx context.Context, grace time.Duration) error {
+	if err := c.image.client.ContainerStop(ctx, c.id, stopOptions(grace)); err != nil {
+		return errorContainerStop(c.id, c.image.image, err)
+	}
+
+	return nil
+}
+
+// Kill sends signal, such as SIGKILL or SIGHUP, to the main process of the container.
+// An empty signal sends SIGKILL.
+func (c *Container) Kill(ctx context.Context, signal string) error {
+	if err := c.image.client.ContainerKill(ctx, c.id, signal); err != nil {
+		return errorContainerKill(c.id, c.image.image, err)
+	}
+
+	return nil
+}
+
+// Restart stops the container as Stop does with grace, and starts it again as Start does,
+// so the writers set by StreamOutput keep receiving its output. The Stdin reader is not streamed again.
+func (c *Container) Restart(ctx context.Context, grace time.Duration) error {
+	if err := c.image.client.ContainerStop(ctx, c.id, stopOptions(grace)); err != nil {
+		return errorContainerRestart(c.id, c.image.image, err)
+	}
+
+	if err := c.Start(ctx); err != nil {
+		return errorContainerRestart(c.id, c.image.image, err)
+	}
+
+	return nil
+}
+
+// Pause suspends all processes of the container.
+func (c *Container) Pause(ctx context.Context) error {
+	if err := c.image.client.ContainerPause(ctx, c.id); err != nil {
+		return errorContainerPause(c.id, c.image.image, err)
+	}
+
+	return nil
+}
+
+// Unpause resumes the processes of a paused container.
+func (c *Container) Unpause(ctx context.Context) error {
+	if err := c.image.client.ContainerUnpause(ctx, c.id); err != nil {
+		return errorContainerUnpause(c.id, c.image.image, err)
+	}
+
+	return nil
+}
+
+// State returns the current state of the container.
+func (c *Container) State(ctx context.Context) (*State, error) {
+	info, err := c.image.client.ContainerInspect(ctx, c.id)
+	if err != nil {
+		return nil, errorContainerInspect(c.id, c.image.image, err)
+	}
+
+	state := info.ContainerJSONBase.State
+	return &State{
+		Status:     state.Status,
+		Running:    state.Running,
+		Paused:     state.Paused,
+		Restarting: state.Restarting,
+		OOMKilled:  state.OOMKilled,
+		Dead:       state.Dead,
+		Pid:        state.Pid,
+		ExitCode:   state.ExitCode,
+		Error:      state.Error,
+		StartedAt:  parseStateTime(state.StartedAt),
+		FinishedAt: parseStateTime(state.FinishedAt),
+	}, nil
+}
+
+func stopOptions(grace time.Duration) container.StopOptions {
+	if grace < 0 {
+		return container.StopOptions{}
+	}
+
+	timeout := int(grace.Round(time.Second) / time.Second)
+	return container.StopOptions{Timeout: &timeout}
+}
+
+// parseStateTime parses a container state timestamp, returning the zero time if it is unset.
+func parseStateTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// Health is the docker health status of a container, and its most recent probes.
+type Health struct {
+	// Status is one of starting, healthy, or unhealthy.
+	Status        string
+	FailingStreak int
+	Probes        []HealthProbe
+}
+
+// HealthProbe is the result of a single run of the health check command.
+type HealthProbe struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
+}
+
+// Health returns the health status of the container, which must define a health check with the image or the HealthCheck option.
+func (c *Container) Health(ctx context.Context) (*Health, error) {
+	info, err := c.image.client.ContainerInspect(ctx, c.id)
+	if err != nil {
+		return nil, errorContainerInspect(c.id, c.image.image, err)
+	}
+
+	if info.State == nil || info.State.Health == nil {
+		return nil, errorContainerHealth(c.id, c.image.image, ErrorNoHealthCheck)
+	}
+
+	health := &Health{
+		Status:        info.State.Health.Status,
+		FailingStreak: info.State.Health.FailingStreak,
+		Probes:        make([]HealthProbe, 0, len(info.State.Health.Log)),
+	}
+	for _, probe := range info.State.Health.Log {
+		if probe == nil {
+			continue
+		}
+
+		health.Probes = append(health.Probes, HealthProbe{
+			Start:    probe.Start,
+			End:      probe.End,
+			ExitCode: probe.ExitCode,
+			Output:   probe.Output,
+		})
+	}
+
+	return health, nil
+}
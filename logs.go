@@ -0,0 +1,196 @@
+package containers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Logs returns the standard Out and Error logs of the container, configured by the log options.
+// Without LogFollow, the logs produced so far are returned; with it, the logs stream until the container stops or ctx is done.
+func (c *Container) Logs(ctx context.Context, options ...LogOption) (*MuxedReadCloser, error) {
+	logsOptions := types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true}
+	for _, opt := range options {
+		if err := opt(&logsOptions); err != nil {
+			return nil, errorLogOptions(c.id, c.image.image, err)
+		}
+	}
+
+	reader, err := c.image.client.ContainerLogs(ctx, c.id, logsOptions)
+	if err != nil {
+		return nil, errorContainerLogs(c.id, c.image.image, err)
+	}
+
+	return &MuxedReadCloser{reader: reader, tty: c.tty, timestamps: logsOptions.Timestamps}, nil
+}
+
+// MaxLogLineSize is the size above which Lines splits a log line into partial lines.
+var MaxLogLineSize = 64 * 1024
+
+// LogStream is the standard stream a log line was written to.
+type LogStream int
+
+const (
+	StreamStdout LogStream = iota + 1
+	StreamStderr
+)
+
+func (s LogStream) String() string {
+	switch s {
+	case StreamStdout:
+		return "stdout"
+	case StreamStderr:
+		return "stderr"
+	default:
+		return "unknown"
+	}
+}
+
+// LogLine is a line of the container logs, without its line ending.
+type LogLine struct {
+	Stream LogStream
+	// Timestamp is set when the logs were read with the LogTimestamps option.
+	Timestamp time.Time
+	Text      string
+	// Partial is set when the line exceeded MaxLogLineSize, and continues in the next line of the same stream.
+	Partial bool
+}
+
+// DecodeJSON decodes the text of the line as JSON into v, for applications logging JSON lines.
+func (l LogLine) DecodeJSON(v any) error {
+	return json.Unmarshal([]byte(l.Text), v)
+}
+
+// Lines returns a channel of the log lines, in the order the docker host sent them, which is closed once the logs end,
+// reading them fails, or ctx is done. Err returns the reason once the channel is closed. The logs are closed with the channel.
+func (mx *MuxedReadCloser) Lines(ctx context.Context) <-chan LogLine {
+	lines := make(chan LogLine)
+	go func() {
+		defer close(lines)
+		defer mx.reader.Close()
+
+		stop := context.AfterFunc(ctx, func() {
+			mx.reader.Close()
+		})
+		defer stop()
+
+		stdOut := &lineWriter{ctx: ctx, lines: lines, stream: StreamStdout, timestamps: mx.timestamps}
+		stdErr := &lineWriter{ctx: ctx, lines: lines, stream: StreamStderr, timestamps: mx.timestamps}
+
+		_, err := mx.copy(stdOut, stdErr)
+		if err == nil {
+			err = errors.Join(stdOut.flush(), stdErr.flush())
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		mx.err = err
+	}()
+
+	return lines
+}
+
+// Err returns the error that ended the channel returned by Lines, once it is closed.
+func (mx *MuxedReadCloser) Err() error {
+	return mx.err
+}
+
+// lineWriter splits the output of a stream into log lines.
+type lineWriter struct {
+	ctx        context.Context
+	lines      chan<- LogLine
+	stream     LogStream
+	timestamps bool
+
+	buf []byte
+	// continued is set when buf continues a partial line, so has no timestamp of its own.
+	continued bool
+	timestamp time.Time
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		chunk := p
+		i := bytes.IndexByte(p, '\n')
+		if i >= 0 {
+			chunk, p = p[:i], p[i+1:]
+		} else {
+			p = nil
+		}
+
+		w.buf = append(w.buf, chunk...)
+		for len(w.buf) > MaxLogLineSize {
+			cut := splitPoint(w.buf, MaxLogLineSize)
+			if err := w.emit(w.buf[:cut], true); err != nil {
+				return 0, err
+			}
+			w.buf = append(w.buf[:0], w.buf[cut:]...)
+		}
+
+		if i >= 0 {
+			if err := w.emit(w.buf, false); err != nil {
+				return 0, err
+			}
+			w.buf = w.buf[:0]
+		}
+	}
+
+	return n, nil
+}
+
+// flush emits the last line of the stream if it did not end with a line ending.
+func (w *lineWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	err := w.emit(w.buf, false)
+	w.buf = nil
+	return err
+}
+
+func (w *lineWriter) emit(text []byte, partial bool) error {
+	line := LogLine{Stream: w.stream, Partial: partial}
+	if w.continued {
+		line.Timestamp = w.timestamp
+	} else if w.timestamps {
+		if prefix, rest, ok := bytes.Cut(text, []byte{' '}); ok {
+			if t, err := time.Parse(time.RFC3339Nano, string(prefix)); err == nil {
+				line.Timestamp, text = t, rest
+			}
+		}
+		w.timestamp = line.Timestamp
+	}
+	w.continued = partial
+
+	if !partial {
+		text = bytes.TrimSuffix(text, []byte{'\r'})
+	}
+	line.Text = string(text)
+
+	select {
+	case w.lines <- line:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// splitPoint returns the index at most size to split buf at, without splitting a UTF-8 encoded rune.
+func splitPoint(buf []byte, size int) int {
+	for cut := size; cut > size-utf8.UTFMax && cut > 0; cut-- {
+		if utf8.RuneStart(buf[cut]) {
+			return cut
+		}
+	}
+
+	return size
+}
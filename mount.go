@@ -0,0 +1,41 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// prepareMounts validates bind mount sources, and creates the named volumes used by the container.
+func (c *Container) prepareMounts(ctx context.Context) error {
+	for _, m := range c.mounts {
+		switch m.Type {
+		case mount.TypeBind:
+			if !filepath.IsAbs(m.Source) {
+				return fmt.Errorf("bind source `%s` must be an absolute path", m.Source)
+			}
+
+			if !c.image.client.isLocal() {
+				continue
+			}
+
+			if _, err := os.Stat(m.Source); err != nil {
+				return fmt.Errorf("bind source `%s` for `%s` failed with: %w", m.Source, m.Target, err)
+			}
+		case mount.TypeVolume:
+			if len(m.Source) == 0 {
+				continue
+			}
+
+			if _, err := c.image.client.VolumeCreate(ctx, volume.CreateOptions{Name: m.Source}); err != nil {
+				return fmt.Errorf("creating volume `%s` failed with: %w", m.Source, err)
+			}
+		}
+	}
+
+	return nil
+}
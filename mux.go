@@ -1,35 +1,243 @@
 package containers
 
 import (
+	"bufio"
+	"errors"
 	"io"
+	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/docker/docker/pkg/stdcopy"
 )
 
+// stdcopy frames start with an 8 byte header: the stream, three zero bytes, and the big endian frame size.
+const muxHeaderLen = 8
+
+// NewMuxedReadCloser wraps container logs, either multiplexed by the docker host or the raw output of a TTY container.
+func NewMuxedReadCloser(reader io.ReadCloser) *MuxedReadCloser {
+	return &MuxedReadCloser{reader: reader}
+}
+
 // Combined returns the Stderr, and Stdout combined container logs.
+// An error reading the logs is returned by the reader once the logs read before it are consumed.
 func (mx *MuxedReadCloser) Combined() io.ReadCloser {
 	r, w := io.Pipe()
 	go func() {
-		defer w.Close()
 		defer mx.reader.Close()
-		stdcopy.StdCopy(w, w, mx.reader)
+		_, err := mx.copy(w, w)
+		w.CloseWithError(err)
 	}()
 	return r
 }
 
 // Separated returns both the standard Out and Error logs of the container.
+// Logs of TTY containers are not separated by the docker host, so they are all returned on the standard Out.
+// Each stream is buffered independently, so they can be read in any order, or not at all. Beyond MaxLogBuffer
+// bytes per stream, unread logs are spooled to a temporary file, removed once the stream is closed.
+// An error reading the logs is returned by both readers once the logs read before it are consumed.
 func (mx *MuxedReadCloser) Separated() (stdOut io.ReadCloser, stdErr io.ReadCloser) {
-	r, w := io.Pipe()
-	rE, wE := io.Pipe()
+	var open atomic.Int32
+	open.Store(2)
+	closed := func() {
+		if open.Add(-1) == 0 {
+			mx.reader.Close()
+		}
+	}
+
+	outSpool, errSpool := newSpool(closed), newSpool(closed)
 	go func() {
-		defer w.Close()
-		defer wE.Close()
 		defer mx.reader.Close()
-		stdcopy.StdCopy(w, wE, mx.reader)
+		_, err := mx.copy(outSpool, errSpool)
+		outSpool.CloseWithError(err)
+		errSpool.CloseWithError(err)
 	}()
-	return r, rE
+	return outSpool, errSpool
 }
 
 func (mx *MuxedReadCloser) Close() error {
 	return mx.reader.Close()
 }
+
+// copy demultiplexes the logs into stdOut and stdErr, or copies them as is to stdOut for raw TTY streams.
+func (mx *MuxedReadCloser) copy(stdOut, stdErr io.Writer) (int64, error) {
+	reader := bufio.NewReader(mx.reader)
+	if mx.tty || isRaw(reader) {
+		return io.Copy(stdOut, reader)
+	}
+
+	return stdcopy.StdCopy(stdOut, stdErr, reader)
+}
+
+// isRaw returns true if the stream does not start with a stdcopy frame header, as is the case for TTY containers.
+func isRaw(reader *bufio.Reader) bool {
+	header, err := reader.Peek(muxHeaderLen)
+	if len(header) == 0 {
+		return false
+	}
+
+	if err != nil || header[0] > byte(stdcopy.Systemerr) {
+		return true
+	}
+
+	return header[1] != 0 || header[2] != 0 || header[3] != 0
+}
+
+// Close closes the logs, and removes the spooled artifacts.
+func (r *RunResult) Close() error {
+	var errs []error
+	if r.MuxedReadCloser != nil {
+		errs = append(errs, r.MuxedReadCloser.Close())
+	}
+
+	for _, artifact := range r.Artifacts {
+		errs = append(errs, artifact.Remove())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Output reads the standard Out and Error logs of the container to completion, and closes them.
+func (r *RunResult) Output() (stdOut []byte, stdErr []byte, err error) {
+	outReader, errReader := r.Separated()
+
+	var errErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stdErr, errErr = io.ReadAll(errReader)
+	}()
+
+	stdOut, err = io.ReadAll(outReader)
+	<-done
+
+	return stdOut, stdErr, errors.Join(err, errErr)
+}
+
+// MaxLogBuffer is the size of the unread logs of a stream returned by Separated held in memory, before spooling to a file.
+var MaxLogBuffer = 4 * 1024 * 1024
+
+// spool is a pipe whose writes never block: data is held in memory up to MaxLogBuffer, then in a temporary file.
+type spool struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	mem  []byte
+	file *os.File
+	// written, and read are the offsets of the data written to, and read from the file.
+	written int64
+	read    int64
+
+	// closed is set once the writer is done, with err its reason.
+	closed bool
+	err    error
+	// discarded is set once the reader is closed, after which writes are dropped.
+	discarded bool
+	onClose   func()
+}
+
+func newSpool(onClose func()) *spool {
+	s := &spool{onClose: onClose}
+	s.cond = sync.NewCond(&s.lock)
+	return s
+}
+
+func (s *spool) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	defer s.cond.Broadcast()
+
+	if s.discarded {
+		return len(p), nil
+	}
+
+	if s.file == nil && len(s.mem)+len(p) <= MaxLogBuffer {
+		s.mem = append(s.mem, p...)
+		return len(p), nil
+	}
+
+	if s.file == nil {
+		file, err := os.CreateTemp("", "container-logs-*")
+		if err != nil {
+			return 0, err
+		}
+		s.file = file
+	}
+
+	n, err := s.file.WriteAt(p, s.written)
+	s.written += int64(n)
+	return n, err
+}
+
+func (s *spool) Read(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for {
+		switch {
+		case s.discarded:
+			return 0, io.ErrClosedPipe
+		case len(s.mem) > 0:
+			n := copy(p, s.mem)
+			if s.mem = s.mem[n:]; len(s.mem) == 0 {
+				s.mem = nil
+			}
+			return n, nil
+		case s.read < s.written:
+			if size := s.written - s.read; int64(len(p)) > size {
+				p = p[:size]
+			}
+			n, err := s.file.ReadAt(p, s.read)
+			s.read += int64(n)
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		case s.closed:
+			s.removeFile()
+			if s.err != nil {
+				return 0, s.err
+			}
+			return 0, io.EOF
+		}
+
+		s.cond.Wait()
+	}
+}
+
+// CloseWithError marks the end of the data written, returning err to the reader once it is consumed, or io.EOF if nil.
+func (s *spool) CloseWithError(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed, s.err = true, err
+	s.cond.Broadcast()
+}
+
+// Close discards the unread data, and removes the spool file.
+func (s *spool) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.discarded {
+		return nil
+	}
+
+	s.discarded, s.mem = true, nil
+	s.cond.Broadcast()
+
+	s.removeFile()
+	if s.onClose != nil {
+		s.onClose()
+	}
+
+	return nil
+}
+
+func (s *spool) removeFile() {
+	if s.file != nil {
+		s.file.Close()
+		os.Remove(s.file.Name())
+		s.file, s.read, s.written = nil, 0, 0
+	}
+}
@@ -0,0 +1,90 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Network creates a docker network with the given name, or returns the existing network of that name.
+// An existing network must match the driver, internal setting, subnets and labels given by the options.
+// Containers join the network with the NetworkMode option, and can reach each other by name or alias.
+func (c *Client) Network(ctx context.Context, name string, options ...NetworkOption) (*Network, error) {
+	network := &Network{
+		client: c,
+		name:   name,
+		create: types.NetworkCreate{Driver: "bridge"},
+	}
+
+	for _, opt := range options {
+		if err := opt(network); err != nil {
+			return nil, errorNetworkOptions(name, err)
+		}
+	}
+
+	if existing, err := c.NetworkInspect(ctx, name, types.NetworkInspectOptions{}); err == nil {
+		if err = network.matches(existing); err != nil {
+			return nil, errorNetworkExists(name, err)
+		}
+
+		network.id = existing.ID
+		return network, nil
+	}
+
+	resp, err := c.NetworkCreate(ctx, name, network.create)
+	if err != nil {
+		return nil, errorNetworkCreate(name, err)
+	}
+	network.id = resp.ID
+
+	return network, nil
+}
+
+// Remove removes the network from the docker host client. Containers attached to it must be removed first.
+func (n *Network) Remove(ctx context.Context) error {
+	if err := n.client.NetworkRemove(ctx, n.id); err != nil {
+		return errorNetworkRemove(n.name, err)
+	}
+
+	return nil
+}
+
+// ID returns the id of the network.
+func (n *Network) ID() string {
+	return n.id
+}
+
+// Name returns the name of the network.
+func (n *Network) Name() string {
+	return n.name
+}
+
+// matches returns an error if the existing network differs from the options of the network.
+func (n *Network) matches(existing types.NetworkResource) error {
+	if existing.Driver != n.create.Driver {
+		return fmt.Errorf("driver is `%s`, expected `%s`", existing.Driver, n.create.Driver)
+	}
+
+	if existing.Internal != n.create.Internal {
+		return fmt.Errorf("internal is %t, expected %t", existing.Internal, n.create.Internal)
+	}
+
+	if n.create.IPAM != nil {
+		for _, requested := range n.create.IPAM.Config {
+			found := false
+			for _, config := range existing.IPAM.Config {
+				if config.Subnet == requested.Subnet && (len(requested.Gateway) == 0 || config.Gateway == requested.Gateway) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("subnet `%s` with gateway `%s` is missing", requested.Subnet, requested.Gateway)
+			}
+		}
+	}
+
+	return checkSubset("label", n.create.Labels, existing.Labels)
+}
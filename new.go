@@ -2,13 +2,20 @@ package containers
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/docker/docker/client"
 )
 
-// New creates a new dockerClient with default Options.
-func New() (dockerClient *Client, err error) {
+// New creates a new dockerClient with default Options, and the given client options.
+func New(options ...ClientOption) (dockerClient *Client, err error) {
 	dockerClient = new(Client)
+	for _, opt := range options {
+		if err = opt(dockerClient); err != nil {
+			return nil, fmt.Errorf("client options failed with: %w", err)
+		}
+	}
+
 	dockerClient.Client, err = client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, fmt.Errorf("new docker client failed with: %w", err)
@@ -16,3 +23,31 @@ func New() (dockerClient *Client, err error) {
 
 	return
 }
+
+// DefaultHelperImage is the image of the short-lived containers used to copy files in and out of volumes.
+var DefaultHelperImage = "busybox:latest"
+
+// helper returns the image of the short-lived containers used to copy files in and out of volumes.
+func (c *Client) helper() string {
+	if len(c.helperImage) > 0 {
+		return c.helperImage
+	}
+
+	return DefaultHelperImage
+}
+
+// isLocal returns true if the docker host is reached through a local socket, so host paths are shared with it.
+func (c *Client) isLocal() bool {
+	host, err := url.Parse(c.DaemonHost())
+	return err == nil && (host.Scheme == "unix" || host.Scheme == "npipe")
+}
+
+// daemonHostname returns the hostname of the docker host, `localhost` for local sockets.
+func (c *Client) daemonHostname() string {
+	host, err := url.Parse(c.DaemonHost())
+	if err != nil || c.isLocal() || len(host.Hostname()) == 0 {
+		return "localhost"
+	}
+
+	return host.Hostname()
+}
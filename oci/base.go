@@ -0,0 +1,229 @@
+package oci
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// dockerManifestFile is the manifest written by `docker save`.
+const dockerManifestFile = "manifest.json"
+
+// dockerManifest is an entry of the manifest written by `docker save`.
+type dockerManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// readBase extracts the base archive and loads its configuration and layers.
+// Both `docker save` archives and OCI image layouts are supported; for multi-image archives the first image is used.
+func (b *Builder) readBase(r io.Reader) error {
+	root := filepath.Join(b.dir, "base")
+	if err := extract(r, root); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(root, dockerManifestFile)); err == nil {
+		return b.readDockerArchive(root)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, ocispec.ImageIndexFile)); err == nil {
+		return b.readLayout(root)
+	}
+
+	return errors.New("base is neither a docker archive nor an OCI image layout")
+}
+
+func (b *Builder) readDockerArchive(root string) error {
+	var manifests []dockerManifest
+	if err := readJSON(filepath.Join(root, dockerManifestFile), &manifests); err != nil {
+		return err
+	}
+
+	if len(manifests) == 0 {
+		return errors.New("docker archive holds no images")
+	}
+
+	manifest := manifests[0]
+	if err := readJSON(filepath.Join(root, filepath.FromSlash(manifest.Config)), &b.image); err != nil {
+		return err
+	}
+
+	if len(manifest.Layers) != len(b.image.RootFS.DiffIDs) {
+		return fmt.Errorf("docker archive lists %d layers for %d diff ids", len(manifest.Layers), len(b.image.RootFS.DiffIDs))
+	}
+
+	for idx, name := range manifest.Layers {
+		blob := filepath.Join(root, filepath.FromSlash(name))
+		dgst, size, err := digestFile(blob)
+		if err != nil {
+			return err
+		}
+
+		mediaType := ocispec.MediaTypeImageLayer
+		if dgst != b.image.RootFS.DiffIDs[idx] {
+			mediaType = ocispec.MediaTypeImageLayerGzip
+		}
+
+		b.layers = append(b.layers, layer{
+			path:      blob,
+			mediaType: mediaType,
+			digest:    dgst,
+			diffID:    b.image.RootFS.DiffIDs[idx],
+			size:      size,
+		})
+	}
+
+	return nil
+}
+
+func (b *Builder) readLayout(root string) error {
+	manifest, err := ResolveManifest(root)
+	if err != nil {
+		return err
+	}
+
+	if err = readJSON(BlobPath(root, manifest.Config.Digest), &b.image); err != nil {
+		return err
+	}
+
+	if len(manifest.Layers) != len(b.image.RootFS.DiffIDs) {
+		return fmt.Errorf("manifest lists %d layers for %d diff ids", len(manifest.Layers), len(b.image.RootFS.DiffIDs))
+	}
+
+	for idx, desc := range manifest.Layers {
+		b.layers = append(b.layers, layer{
+			path:      BlobPath(root, desc.Digest),
+			mediaType: desc.MediaType,
+			digest:    desc.Digest,
+			diffID:    b.image.RootFS.DiffIDs[idx],
+			size:      desc.Size,
+		})
+	}
+
+	return nil
+}
+
+// ResolveManifest returns the first image manifest referenced by the index of the OCI image layout at root,
+// descending through nested indexes.
+func ResolveManifest(root string) (*ocispec.Manifest, error) {
+	var index ocispec.Index
+	if err := readJSON(filepath.Join(root, ocispec.ImageIndexFile), &index); err != nil {
+		return nil, err
+	}
+
+	for {
+		if len(index.Manifests) == 0 {
+			return nil, errors.New("image index holds no manifests")
+		}
+
+		desc := index.Manifests[0]
+		switch desc.MediaType {
+		case ocispec.MediaTypeImageIndex:
+			index = ocispec.Index{}
+			if err := readJSON(BlobPath(root, desc.Digest), &index); err != nil {
+				return nil, err
+			}
+		case ocispec.MediaTypeImageManifest, dockerManifestMediaType:
+			var manifest ocispec.Manifest
+			if err := readJSON(BlobPath(root, desc.Digest), &manifest); err != nil {
+				return nil, err
+			}
+
+			return &manifest, nil
+		default:
+			return nil, fmt.Errorf("unsupported media type `%s`", desc.MediaType)
+		}
+	}
+}
+
+// BlobPath returns the path of the blob with the given digest in the OCI image layout at root.
+func BlobPath(root string, dgst digest.Digest) string {
+	return filepath.Join(root, ocispec.ImageBlobsDir, dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// extract writes the regular files and directories of the tarball under root.
+func extract(r io.Reader, root string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := cleanPath(header.Name)
+		if len(name) == 0 {
+			continue
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(file, r); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func digestFile(name string) (digest.Digest, int64, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(digester.Hash(), file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return digester.Digest(), size, nil
+}
+
+func readJSON(name string, v any) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding `%s` failed with: %w", filepath.Base(name), err)
+	}
+
+	return nil
+}
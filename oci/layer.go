@@ -0,0 +1,214 @@
+package oci
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// File defines a file to be written into an image layer.
+type File struct {
+	Path string
+	Data []byte
+	// Mode defaults to 0644 when left empty.
+	Mode fs.FileMode
+	UID  int
+	GID  int
+}
+
+// epoch is used as the modification time of all entries so layers are reproducible.
+var epoch = time.Unix(0, 0).UTC()
+
+// spoolLayer writes the layer produced by source to the assembly directory, computing its digest.
+func (b *Builder) spoolLayer(source func(w io.Writer) error) (layer, error) {
+	file, err := os.CreateTemp(b.dir, "layer-")
+	if err != nil {
+		return layer{}, err
+	}
+	defer file.Close()
+
+	digester := digest.Canonical.Digester()
+	counter := &countWriter{writer: io.MultiWriter(file, digester.Hash())}
+	if err = source(counter); err != nil {
+		return layer{}, err
+	}
+
+	dgst := digester.Digest()
+	return layer{
+		path:      file.Name(),
+		mediaType: ocispec.MediaTypeImageLayer,
+		digest:    dgst,
+		diffID:    dgst,
+		size:      counter.count,
+	}, nil
+}
+
+// writeFiles writes the given files, and their parent directories, as a tarball.
+func writeFiles(w io.Writer, files []File) error {
+	sorted := make([]File, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return cleanPath(sorted[i].Path) < cleanPath(sorted[j].Path)
+	})
+
+	tw := tar.NewWriter(w)
+	dirs := make(map[string]bool)
+	for _, file := range sorted {
+		name := cleanPath(file.Path)
+		if err := writeParents(tw, dirs, name); err != nil {
+			return err
+		}
+
+		mode := file.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     int64(len(file.Data)),
+			Mode:     int64(mode.Perm()),
+			Uid:      file.UID,
+			Gid:      file.GID,
+			ModTime:  epoch,
+			Format:   tar.FormatPAX,
+		}); err != nil {
+			return fmt.Errorf("writing header of `%s` failed with: %w", name, err)
+		}
+
+		if _, err := tw.Write(file.Data); err != nil {
+			return fmt.Errorf("writing `%s` failed with: %w", name, err)
+		}
+	}
+
+	return tw.Close()
+}
+
+// writeDirectory writes the contents of the local directory source as a tarball, rooted at target.
+func writeDirectory(w io.Writer, source, target string) error {
+	tw := tar.NewWriter(w)
+	dirs := make(map[string]bool)
+	target = cleanPath(target)
+
+	err := filepath.WalkDir(source, func(local string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, local)
+		if err != nil {
+			return err
+		}
+
+		name := cleanPath(path.Join(target, filepath.ToSlash(rel)))
+		if len(name) == 0 {
+			return nil
+		}
+
+		if err = writeParents(tw, dirs, name); err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(local); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = name
+		header.ModTime = epoch
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		header.Uid, header.Gid = 0, 0
+		header.Uname, header.Gname = "", ""
+		header.Format = tar.FormatPAX
+		if entry.IsDir() {
+			header.Name += "/"
+			dirs[name] = true
+		}
+
+		if err = tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("writing header of `%s` failed with: %w", name, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(local)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err = io.Copy(tw, file); err != nil {
+			return fmt.Errorf("writing `%s` failed with: %w", name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reading directory `%s` failed with: %w", source, err)
+	}
+
+	return tw.Close()
+}
+
+// writeParents writes directory entries for the parents of name that have not been written yet.
+func writeParents(tw *tar.Writer, dirs map[string]bool, name string) error {
+	parent := path.Dir(name)
+	if parent == "." || dirs[parent] {
+		return nil
+	}
+
+	if err := writeParents(tw, dirs, parent); err != nil {
+		return err
+	}
+
+	dirs[parent] = true
+	return tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     parent + "/",
+		Mode:     0755,
+		ModTime:  epoch,
+		Format:   tar.FormatPAX,
+	})
+}
+
+// cleanPath returns the given path relative to the root of the image filesystem.
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+type countWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,120 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Builder assembles an image from an optional base image and file layers, without a docker daemon.
+type Builder struct {
+	dir    string
+	image  ocispec.Image
+	layers []layer
+}
+
+// layer is a blob spooled to disk along with its descriptor information.
+type layer struct {
+	path      string
+	mediaType string
+	digest    digest.Digest
+	diffID    digest.Digest
+	size      int64
+}
+
+// New assembles an image from the given options. Layers are spooled to a temporary directory
+// which is removed by calling Close.
+func New(options ...Option) (*Builder, error) {
+	cnf := &config{
+		platform: ocispec.Platform{
+			OS:           "linux",
+			Architecture: runtime.GOARCH,
+		},
+	}
+	for _, opt := range options {
+		if err := opt(cnf); err != nil {
+			return nil, fmt.Errorf("assembly options failed with: %w", err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "oci-assembly-")
+	if err != nil {
+		return nil, fmt.Errorf("creating assembly directory failed with: %w", err)
+	}
+
+	b := &Builder{
+		dir: dir,
+		image: ocispec.Image{
+			Platform: cnf.platform,
+			RootFS:   ocispec.RootFS{Type: "layers"},
+		},
+	}
+
+	if err = b.assemble(cnf); err != nil {
+		b.Close()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *Builder) assemble(cnf *config) error {
+	switch {
+	case cnf.base != nil && len(cnf.layout) > 0:
+		return errors.New("base and layout are mutually exclusive")
+	case cnf.base != nil:
+		if err := b.readBase(cnf.base); err != nil {
+			return fmt.Errorf("reading base image failed with: %w", err)
+		}
+	case len(cnf.layout) > 0:
+		if err := b.readLayout(cnf.layout); err != nil {
+			return fmt.Errorf("reading layout `%s` failed with: %w", cnf.layout, err)
+		}
+	}
+
+	if (cnf.base != nil || len(cnf.layout) > 0) && cnf.platformSet {
+		b.image.Platform = cnf.platform
+	}
+
+	for idx, source := range cnf.layers {
+		l, err := b.spoolLayer(source)
+		if err != nil {
+			return fmt.Errorf("creating layer %d failed with: %w", idx, err)
+		}
+
+		b.layers = append(b.layers, l)
+		b.image.History = append(b.image.History, ocispec.History{
+			Created:   cnf.created,
+			CreatedBy: createdBy,
+		})
+	}
+
+	for _, set := range cnf.setters {
+		set(&b.image.Config)
+	}
+
+	if cnf.created != nil {
+		b.image.Created = cnf.created
+	}
+
+	b.image.RootFS.DiffIDs = make([]digest.Digest, len(b.layers))
+	for idx, l := range b.layers {
+		b.image.RootFS.DiffIDs[idx] = l.diffID
+	}
+
+	return nil
+}
+
+// Config returns the image configuration of the assembled image.
+func (b *Builder) Config() ocispec.Image {
+	return b.image
+}
+
+// Close removes the spooled layers of the assembled image.
+func (b *Builder) Close() error {
+	return os.RemoveAll(b.dir)
+}
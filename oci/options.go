@@ -0,0 +1,180 @@
+package oci
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const createdBy = "go-simple-container"
+
+type config struct {
+	base        io.Reader
+	layout      string
+	layers      []func(w io.Writer) error
+	setters     []func(*ocispec.ImageConfig)
+	platform    ocispec.Platform
+	platformSet bool
+	created     *time.Time
+}
+
+// Option is a function to set configuration of the assembled image.
+type Option func(o *config) error
+
+// Base sets the image the assembly is built upon, given as a `docker save` or OCI archive tarball.
+// Without a base the image is assembled from scratch.
+func Base(tarball io.Reader) Option {
+	return func(o *config) error {
+		if tarball == nil {
+			return errors.New("base tarball is nil")
+		}
+
+		o.base = tarball
+		return nil
+	}
+}
+
+// Layout sets the image the assembly is built upon, read from the OCI image layout directory dir.
+func Layout(dir string) Option {
+	return func(o *config) error {
+		if len(dir) == 0 {
+			return errors.New("layout directory is empty")
+		}
+
+		o.layout = dir
+		return nil
+	}
+}
+
+// Files adds a layer holding the given files.
+func Files(files ...File) Option {
+	return func(o *config) error {
+		for _, file := range files {
+			if len(cleanPath(file.Path)) == 0 {
+				return errors.New("file path is empty")
+			}
+		}
+
+		o.layers = append(o.layers, func(w io.Writer) error {
+			return writeFiles(w, files)
+		})
+		return nil
+	}
+}
+
+// Directory adds a layer holding the contents of the local directory source, placed at target in the image.
+func Directory(source, target string) Option {
+	return func(o *config) error {
+		o.layers = append(o.layers, func(w io.Writer) error {
+			return writeDirectory(w, source, target)
+		})
+		return nil
+	}
+}
+
+// Layer adds an uncompressed tarball as a layer as is.
+func Layer(tarball io.Reader) Option {
+	return func(o *config) error {
+		if tarball == nil {
+			return errors.New("layer tarball is nil")
+		}
+
+		o.layers = append(o.layers, func(w io.Writer) error {
+			_, err := io.Copy(w, tarball)
+			return err
+		})
+		return nil
+	}
+}
+
+// Platform sets the operating system and architecture of the image, defaults to linux on the current architecture.
+func Platform(os, architecture string) Option {
+	return func(o *config) error {
+		o.platform = ocispec.Platform{
+			OS:           os,
+			Architecture: architecture,
+		}
+		o.platformSet = true
+		return nil
+	}
+}
+
+// Created sets the creation time of the image, and of the layers it adds.
+func Created(t time.Time) Option {
+	return func(o *config) error {
+		o.created = &t
+		return nil
+	}
+}
+
+// Entrypoint sets the entrypoint of the image.
+func Entrypoint(entrypoint ...string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		c.Entrypoint = entrypoint
+	})
+}
+
+// Command sets the default command of the image.
+func Command(cmd ...string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		c.Cmd = cmd
+	})
+}
+
+// Variable sets an environment variable in the image, replacing any inherited value.
+func Variable(key, value string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		prefix := key + "="
+		for idx, env := range c.Env {
+			if len(env) >= len(prefix) && env[:len(prefix)] == prefix {
+				c.Env[idx] = prefix + value
+				return
+			}
+		}
+
+		c.Env = append(c.Env, prefix+value)
+	})
+}
+
+// WorkDir sets the working directory of the image.
+func WorkDir(workDir string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		c.WorkingDir = workDir
+	})
+}
+
+// User sets the user, and optionally group, the image runs as.
+func User(user string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		c.User = user
+	})
+}
+
+// Label sets a label on the image.
+func Label(key, value string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		if c.Labels == nil {
+			c.Labels = make(map[string]string)
+		}
+		c.Labels[key] = value
+	})
+}
+
+// ExposedPort declares a port, such as `8080/tcp`, exposed by the image.
+func ExposedPort(port string) Option {
+	return setter(func(c *ocispec.ImageConfig) {
+		if c.ExposedPorts == nil {
+			c.ExposedPorts = make(map[string]struct{})
+		}
+		c.ExposedPorts[port] = struct{}{}
+	})
+}
+
+func setter(set func(*ocispec.ImageConfig)) Option {
+	return func(o *config) error {
+		o.setters = append(o.setters, set)
+		return nil
+	}
+}
@@ -0,0 +1,237 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	// containerdImageNameAnnotation holds the fully qualified image name, as read by `docker load`.
+	containerdImageNameAnnotation = "io.containerd.image.name"
+)
+
+// Write writes the assembled image as a tarball tagged with the given references.
+// The tarball holds both a `docker save` manifest and an OCI image layout, so it can be passed
+// to the docker load API or to any tooling that reads OCI archives.
+func (b *Builder) Write(w io.Writer, tags ...string) error {
+	tw := tar.NewWriter(w)
+	if err := b.write(tarSink{tw}, tags); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// WriteLayout writes the assembled image as an OCI image layout in dir, tagged with the given references.
+// The directory is created if needed, and an existing index is replaced.
+func (b *Builder) WriteLayout(dir string, tags ...string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating layout directory failed with: %w", err)
+	}
+
+	return b.write(dirSink(dir), tags)
+}
+
+func (b *Builder) write(s sink, tags []string) error {
+	names := make([]reference.NamedTagged, len(tags))
+	for idx, tag := range tags {
+		named, err := reference.ParseNormalizedNamed(tag)
+		if err != nil {
+			return fmt.Errorf("parsing tag `%s` failed with: %w", tag, err)
+		}
+
+		tagged, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+		if !ok {
+			return fmt.Errorf("tag `%s` must not hold a digest", tag)
+		}
+
+		names[idx] = tagged
+	}
+
+	configData, err := json.Marshal(b.image)
+	if err != nil {
+		return fmt.Errorf("encoding image config failed with: %w", err)
+	}
+
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    descriptor(ocispec.MediaTypeImageConfig, configData),
+		Layers:    make([]ocispec.Descriptor, len(b.layers)),
+	}
+	for idx, l := range b.layers {
+		manifest.Layers[idx] = ocispec.Descriptor{
+			MediaType: l.mediaType,
+			Digest:    l.digest,
+			Size:      l.size,
+		}
+	}
+
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("encoding image manifest failed with: %w", err)
+	}
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	docker := dockerManifest{Config: blobName(manifest.Config.Digest)}
+	for _, l := range b.layers {
+		docker.Layers = append(docker.Layers, blobName(l.digest))
+	}
+
+	for _, name := range names {
+		desc := descriptor(ocispec.MediaTypeImageManifest, manifestData)
+		desc.Annotations = map[string]string{
+			ocispec.AnnotationRefName:     name.Tag(),
+			containerdImageNameAnnotation: name.String(),
+		}
+		index.Manifests = append(index.Manifests, desc)
+		docker.RepoTags = append(docker.RepoTags, reference.FamiliarString(name))
+	}
+	if len(names) == 0 {
+		index.Manifests = append(index.Manifests, descriptor(ocispec.MediaTypeImageManifest, manifestData))
+	}
+
+	if err = writeLayoutFiles(s, index, [][]byte{configData, manifestData}); err != nil {
+		return err
+	}
+
+	written := make(map[digest.Digest]bool)
+	for _, l := range b.layers {
+		if written[l.digest] {
+			continue
+		}
+		written[l.digest] = true
+
+		if err = writeBlobFile(s, l); err != nil {
+			return err
+		}
+	}
+
+	dockerData, err := json.Marshal([]dockerManifest{docker})
+	if err != nil {
+		return fmt.Errorf("encoding docker manifest failed with: %w", err)
+	}
+
+	return writeEntry(s, dockerManifestFile, dockerData)
+}
+
+// writeLayoutFiles writes the OCI layout marker, index, and given JSON blobs.
+func writeLayoutFiles(s sink, index ocispec.Index, blobs [][]byte) error {
+	layoutData, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("encoding image index failed with: %w", err)
+	}
+
+	if err = writeEntry(s, ocispec.ImageLayoutFile, layoutData); err != nil {
+		return err
+	}
+
+	if err = writeEntry(s, ocispec.ImageIndexFile, indexData); err != nil {
+		return err
+	}
+
+	for _, blob := range blobs {
+		if err = writeEntry(s, blobName(digest.FromBytes(blob)), blob); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeBlobFile(s sink, l layer) error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = s.entry(blobName(l.digest), l.size, file); err != nil {
+		return fmt.Errorf("writing layer `%s` failed with: %w", l.digest, err)
+	}
+
+	return nil
+}
+
+func writeEntry(s sink, name string, data []byte) error {
+	if err := s.entry(name, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("writing `%s` failed with: %w", name, err)
+	}
+
+	return nil
+}
+
+// sink receives the files of a written image.
+type sink interface {
+	entry(name string, size int64, r io.Reader) error
+}
+
+// tarSink writes files as entries of a tarball.
+type tarSink struct {
+	tw *tar.Writer
+}
+
+func (s tarSink) entry(name string, size int64, r io.Reader) error {
+	if err := s.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  epoch,
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(s.tw, r)
+	return err
+}
+
+// dirSink writes files under a directory.
+type dirSink string
+
+func (s dirSink) entry(name string, size int64, r io.Reader) error {
+	target := filepath.Join(string(s), filepath.FromSlash(name))
+
+	// blobs are content addressed, so an existing blob of the same size is left in place
+	if strings.HasPrefix(name, ocispec.ImageBlobsDir+"/") {
+		if info, err := os.Stat(target); err == nil && info.Size() == size {
+			return nil
+		}
+	}
+
+	return writeFile(target, r)
+}
+
+func descriptor(mediaType string, data []byte) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+}
+
+// blobName returns the path of the blob inside an archive.
+func blobName(dgst digest.Digest) string {
+	return path.Join(ocispec.ImageBlobsDir, dgst.Algorithm().String(), dgst.Encoded())
+}
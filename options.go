@@ -1,6 +1,81 @@
 package containers
 
-import "io"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/taubyte/go-simple-container/oci"
+)
+
+/**************** Client Options ****************/
+
+// ClientOption is a function to set configuration to the Client object.
+type ClientOption func(*Client) error
+
+// Rewrite maps references starting with prefix to start with replacement instead, when checking and pulling images.
+// Prefixes are matched against the fully qualified reference, such as `docker.io/library/node:latest`, and the first matching rule applies.
+func Rewrite(prefix, replacement string) ClientOption {
+	return func(c *Client) error {
+		if len(prefix) == 0 {
+			return errors.New("rewrite prefix is empty")
+		}
+
+		c.rewrites = append(c.rewrites, rewrite{
+			prefix:      prefix,
+			replacement: replacement,
+		})
+		return nil
+	}
+}
+
+// Mirrors sets the mirrors images from the given registry, such as `docker.io`, are pulled from.
+// Mirrors are tried in order, falling back to the registry itself.
+func Mirrors(registry string, mirrors ...string) ClientOption {
+	return func(c *Client) error {
+		if len(registry) == 0 {
+			return errors.New("mirrored registry is empty")
+		}
+
+		for _, mirror := range mirrors {
+			if len(mirror) == 0 || strings.Contains(mirror, "://") {
+				return fmt.Errorf("mirror `%s` must be a registry host", mirror)
+			}
+		}
+
+		if c.mirrors == nil {
+			c.mirrors = make(map[string][]string)
+		}
+
+		c.mirrors[registry] = append(c.mirrors[registry], mirrors...)
+		return nil
+	}
+}
+
+// HelperImage sets the image of the short-lived containers used to copy files in and out of volumes, defaults to DefaultHelperImage.
+func HelperImage(name string) ClientOption {
+	return func(c *Client) error {
+		if _, err := ParseReference(name); err != nil {
+			return err
+		}
+
+		c.helperImage = name
+		return nil
+	}
+}
 
 /**************** Image Options ****************/
 
@@ -15,6 +90,32 @@ func Build(tarball io.Reader) ImageOption {
 	}
 }
 
+// Assemble returns an ImageOption to load an image assembled without a Dockerfile.
+// The assembly is tagged with the image name, and is only loaded if the image is unknown or ForceRebuild is set.
+func Assemble(assembly *oci.Builder) ImageOption {
+	return func(i *Image) error {
+		if assembly == nil {
+			return errors.New("assembly is nil")
+		}
+
+		i.assembly = assembly
+		return nil
+	}
+}
+
+// OCILayout returns an ImageOption to load the image from an OCI image layout directory, such as one written by ExportOCI.
+// The first image of the layout is tagged with the image name, and is only loaded if the image is unknown or ForceRebuild is set.
+func OCILayout(dir string) ImageOption {
+	return func(i *Image) error {
+		if len(dir) == 0 {
+			return errors.New("layout directory is empty")
+		}
+
+		i.layout = dir
+		return nil
+	}
+}
+
 /**************** Container Options ****************/
 
 // ContainerOption is a function to set configuration to the Container object.
@@ -45,13 +146,49 @@ func Command(cmd []string) ContainerOption {
 }
 
 // Volume sets local directories to be volumed in the container.
-func Volume(sourcePath, containerPath string) ContainerOption {
+// The source path must be absolute, and is checked to exist when the docker host is local.
+func Volume(sourcePath, containerPath string, options ...MountOption) ContainerOption {
+	return mountOption(mount.Mount{
+		Type:   mount.TypeBind,
+		Source: sourcePath,
+		Target: containerPath,
+	}, options)
+}
+
+// MountVolume mounts the named docker volume in the container, the volume is created if it does not exist.
+func MountVolume(name, containerPath string, options ...MountOption) ContainerOption {
+	return mountOption(mount.Mount{
+		Type:   mount.TypeVolume,
+		Source: name,
+		Target: containerPath,
+	}, options)
+}
+
+// Tmpfs mounts an in-memory filesystem in the container.
+// A later mount on the same container path replaces an earlier one, for any of the mount options.
+func Tmpfs(containerPath string, options ...MountOption) ContainerOption {
+	return mountOption(mount.Mount{
+		Type:   mount.TypeTmpfs,
+		Target: containerPath,
+	}, options)
+}
+
+func mountOption(m mount.Mount, options []MountOption) ContainerOption {
 	return func(c *Container) error {
-		c.volumes = append(c.volumes, volume{
-			source: sourcePath,
-			target: containerPath,
-		},
-		)
+		for _, opt := range options {
+			if err := opt(&m); err != nil {
+				return fmt.Errorf("mount `%s` options failed with: %w", m.Target, err)
+			}
+		}
+
+		for i, existing := range c.mounts {
+			if existing.Target == m.Target {
+				c.mounts[i] = m
+				return nil
+			}
+		}
+
+		c.mounts = append(c.mounts, m)
 		return nil
 	}
 }
@@ -74,6 +211,917 @@ func Variables(vars map[string]string) ContainerOption {
 	}
 }
 
+// Stdin sets the reader streamed into the container's standard input by Run, or the first Start.
+// The container's standard input is closed once the reader reaches EOF, or once the container stops.
+// The reader is never closed, so it can be shared, such as os.Stdin.
+func Stdin(reader io.Reader) ContainerOption {
+	return func(c *Container) error {
+		if reader == nil {
+			return errors.New("stdin reader is nil")
+		}
+
+		c.stdin = reader
+		return nil
+	}
+}
+
+// TTY allocates a pseudo terminal of the given size for the container, for tools that only behave well in a terminal.
+// Output of TTY containers is not separated into standard Out and Error.
+func TTY(cols, rows uint) ContainerOption {
+	return func(c *Container) error {
+		c.tty = true
+		c.hostConfig.ConsoleSize = [2]uint{rows, cols}
+		return nil
+	}
+}
+
+// Files copies the files of the sources into the container under the directory dst, after it is created and before it is started.
+func Files(dst string, sources ...Source) ContainerOption {
+	return func(c *Container) error {
+		for _, src := range sources {
+			if src == nil {
+				return ErrorNilSource
+			}
+
+			c.files = append(c.files, copyIn{dst: dst, src: src})
+		}
+		return nil
+	}
+}
+
+// CollectArtifacts sets paths inside the container that Run copies out once the container exits, before it is removed.
+// The artifacts are returned in the Artifacts field of the RunResult, and are removed by its Close method.
+func CollectArtifacts(paths ...string) ContainerOption {
+	return func(c *Container) error {
+		c.artifacts = append(c.artifacts, paths...)
+		return nil
+	}
+}
+
+// HealthCheck sets the command docker runs inside the container to probe its health, overriding any health check of the image.
+// The command is run directly, unless it starts with CMD-SHELL, CMD, or NONE as in a Dockerfile HEALTHCHECK.
+// The probe runs every interval, fails after timeout, and marks the container unhealthy after retries consecutive failures.
+// Failures within startPeriod of the start do not count. Zero values use the docker defaults.
+func HealthCheck(cmd []string, interval, timeout time.Duration, retries int, startPeriod time.Duration) ContainerOption {
+	return func(c *Container) error {
+		if len(cmd) == 0 {
+			return errors.New("health check command is empty")
+		}
+
+		if retries < 0 {
+			return fmt.Errorf("invalid health check retries %d", retries)
+		}
+
+		test := cmd
+		switch cmd[0] {
+		case "CMD", "CMD-SHELL", "NONE":
+		default:
+			test = append([]string{"CMD"}, cmd...)
+		}
+
+		c.config.Healthcheck = &container.HealthConfig{
+			Test:        test,
+			Interval:    interval,
+			Timeout:     timeout,
+			Retries:     retries,
+			StartPeriod: startPeriod,
+		}
+		return nil
+	}
+}
+
+// StreamOutput sets writers that receive the standard Out and Error of the container in real time, as it produces them,
+// once started by Run or Start. Either writer may be nil. The output of TTY containers is all written to stdout.
+// Run still returns the complete logs once the container exits.
+func StreamOutput(stdout, stderr io.Writer) ContainerOption {
+	return func(c *Container) error {
+		c.stdout = stdout
+		c.stderr = stderr
+		return nil
+	}
+}
+
+// KeepContainer prevents Run from removing the container once it exits, so it can still be committed or inspected.
+// The caller is responsible for calling Cleanup.
+func KeepContainer() ContainerOption {
+	return func(c *Container) error {
+		c.keep = true
+		return nil
+	}
+}
+
+/**************** Container Identity Options ****************/
+
+// Name sets the name of the container.
+func Name(name string) ContainerOption {
+	return func(c *Container) error {
+		c.name = name
+		return nil
+	}
+}
+
+// Label sets a label on the container.
+func Label(key, value string) ContainerOption {
+	return func(c *Container) error {
+		if c.config.Labels == nil {
+			c.config.Labels = make(map[string]string)
+		}
+
+		c.config.Labels[key] = value
+		return nil
+	}
+}
+
+// Labels sets multiple labels on the container.
+func Labels(labels map[string]string) ContainerOption {
+	return func(c *Container) error {
+		for key, value := range labels {
+			Label(key, value)(c)
+		}
+		return nil
+	}
+}
+
+// User sets the user, name or uid, and optionally the group, name or gid, the container runs as.
+func User(user, group string) ContainerOption {
+	return func(c *Container) error {
+		if len(user) == 0 {
+			return errors.New("user is empty")
+		}
+
+		c.config.User = user
+		if len(group) > 0 {
+			c.config.User += ":" + group
+		}
+		return nil
+	}
+}
+
+// Entrypoint overrides the entrypoint of the image.
+func Entrypoint(entrypoint []string) ContainerOption {
+	return func(c *Container) error {
+		c.config.Entrypoint = entrypoint
+		return nil
+	}
+}
+
+// Domainname sets the domain name of the container.
+func Domainname(domainname string) ContainerOption {
+	return func(c *Container) error {
+		c.config.Domainname = domainname
+		return nil
+	}
+}
+
+// StopSignal sets the signal sent to stop the container, such as `SIGINT`.
+func StopSignal(signal string) ContainerOption {
+	return func(c *Container) error {
+		c.config.StopSignal = signal
+		return nil
+	}
+}
+
+/**************** Security Options ****************/
+
+// HardenedPidsLimit is the process limit set by the Hardened option.
+var HardenedPidsLimit int64 = 512
+
+// Hardened bundles defaults for running untrusted workloads: all capabilities dropped, no new privileges,
+// a read-only root filesystem with a writable tmpfs at /tmp, and a process limit.
+// Each setting can be overridden by options given after Hardened, such as CapAdd for a specific capability,
+// ReadOnlyRootfs(false) for a writable root filesystem, or Tmpfs("/tmp", ...) to replace the tmpfs mount.
+func Hardened() ContainerOption {
+	return func(c *Container) error {
+		for _, opt := range []ContainerOption{
+			CapDrop("ALL"),
+			NoNewPrivileges(true),
+			ReadOnlyRootfs(true),
+			Tmpfs("/tmp", TmpfsMode(01777)),
+			PidsLimit(HardenedPidsLimit),
+			Privileged(false),
+		} {
+			if err := opt(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CapDrop drops linux capabilities, such as `NET_RAW`, or `ALL`, from the container.
+func CapDrop(capabilities ...string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.CapDrop = append(c.hostConfig.CapDrop, capabilities...)
+		return nil
+	}
+}
+
+// CapAdd adds linux capabilities, such as `NET_BIND_SERVICE`, to the container.
+func CapAdd(capabilities ...string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.CapAdd = append(c.hostConfig.CapAdd, capabilities...)
+		return nil
+	}
+}
+
+// ReadOnlyRootfs sets whether the root filesystem of the container is mounted read-only.
+func ReadOnlyRootfs(readOnly bool) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.ReadonlyRootfs = readOnly
+		return nil
+	}
+}
+
+// NoNewPrivileges sets whether processes in the container are prevented from gaining privileges,
+// such as through setuid binaries.
+func NoNewPrivileges(enabled bool) ContainerOption {
+	return securityOpt("no-new-privileges", strconv.FormatBool(enabled))
+}
+
+// SeccompProfile sets the seccomp profile of the container, given as inline JSON, a path to a JSON profile, or `unconfined`.
+func SeccompProfile(profile string) ContainerOption {
+	return func(c *Container) error {
+		if trimmed := strings.TrimSpace(profile); profile != "unconfined" && !strings.HasPrefix(trimmed, "{") {
+			data, err := os.ReadFile(profile)
+			if err != nil {
+				return fmt.Errorf("reading seccomp profile failed with: %w", err)
+			}
+			profile = string(data)
+		}
+
+		if profile != "unconfined" && !json.Valid([]byte(profile)) {
+			return errors.New("seccomp profile is not valid JSON")
+		}
+
+		return securityOpt("seccomp", profile)(c)
+	}
+}
+
+// AppArmorProfile sets the AppArmor profile of the container, by name, a path to a profile file, or `unconfined`.
+// Docker cannot load profiles, so the profile must already be loaded on the docker host, such as with
+// `apparmor_parser -r <path>`; given a path, the profile name is read from its `profile` declaration.
+func AppArmorProfile(profile string) ContainerOption {
+	return func(c *Container) error {
+		if strings.ContainsRune(profile, os.PathSeparator) {
+			name, err := appArmorProfileName(profile)
+			if err != nil {
+				return err
+			}
+			profile = name
+		}
+
+		return securityOpt("apparmor", profile)(c)
+	}
+}
+
+func appArmorProfileName(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading apparmor profile failed with: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "profile" {
+			return strings.TrimSuffix(fields[1], "{"), nil
+		}
+	}
+
+	return "", fmt.Errorf("apparmor profile `%s` has no `profile <name>` declaration", path)
+}
+
+// UsernsMode sets the user namespace mode of the container, such as `host`.
+func UsernsMode(mode string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.UsernsMode = container.UsernsMode(mode)
+		return nil
+	}
+}
+
+// Privileged gives the container extended privileges on the host, including all capabilities and devices.
+// Containers are not privileged unless this is explicitly set.
+func Privileged(privileged bool) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.Privileged = privileged
+		return nil
+	}
+}
+
+// securityOpt sets the security option key, replacing any previous value.
+func securityOpt(key, value string) ContainerOption {
+	return func(c *Container) error {
+		opts := c.hostConfig.SecurityOpt[:0]
+		for _, opt := range c.hostConfig.SecurityOpt {
+			if !strings.HasPrefix(opt, key+"=") && !strings.HasPrefix(opt, key+":") {
+				opts = append(opts, opt)
+			}
+		}
+
+		c.hostConfig.SecurityOpt = append(opts, key+"="+value)
+		return nil
+	}
+}
+
+/**************** Mount Options ****************/
+
+// MountOption is a function to set configuration to a mount of the Container.
+type MountOption func(*mount.Mount) error
+
+// ReadOnly mounts the volume read-only.
+func ReadOnly() MountOption {
+	return func(m *mount.Mount) error {
+		m.ReadOnly = true
+		return nil
+	}
+}
+
+// Propagation sets the propagation of a bind mount, such as `rshared` or `rslave`.
+func Propagation(propagation mount.Propagation) MountOption {
+	return func(m *mount.Mount) error {
+		if m.Type != mount.TypeBind {
+			return errors.New("propagation only applies to bind mounts")
+		}
+
+		if m.BindOptions == nil {
+			m.BindOptions = &mount.BindOptions{}
+		}
+
+		m.BindOptions.Propagation = propagation
+		return nil
+	}
+}
+
+// TmpfsSize sets the size of a tmpfs mount, in bytes.
+func TmpfsSize(size int64) MountOption {
+	return func(m *mount.Mount) error {
+		if m.Type != mount.TypeTmpfs {
+			return errors.New("size only applies to tmpfs mounts")
+		}
+
+		if m.TmpfsOptions == nil {
+			m.TmpfsOptions = &mount.TmpfsOptions{}
+		}
+
+		m.TmpfsOptions.SizeBytes = size
+		return nil
+	}
+}
+
+// TmpfsMode sets the permissions of a tmpfs mount, such as 01777.
+func TmpfsMode(mode os.FileMode) MountOption {
+	return func(m *mount.Mount) error {
+		if m.Type != mount.TypeTmpfs {
+			return errors.New("mode only applies to tmpfs mounts")
+		}
+
+		if m.TmpfsOptions == nil {
+			m.TmpfsOptions = &mount.TmpfsOptions{}
+		}
+
+		m.TmpfsOptions.Mode = mode
+		return nil
+	}
+}
+
+/**************** Resource Options ****************/
+
+// Memory limits the memory of the container, in bytes.
+func Memory(limit int64) ContainerOption {
+	return func(c *Container) error {
+		if limit < 0 {
+			return fmt.Errorf("memory limit %d is negative", limit)
+		}
+
+		c.hostConfig.Memory = limit
+		return nil
+	}
+}
+
+// MemorySwap limits the total memory and swap of the container, in bytes. Set -1 for unlimited swap.
+func MemorySwap(limit int64) ContainerOption {
+	return func(c *Container) error {
+		if limit < -1 {
+			return fmt.Errorf("memory swap limit %d is invalid", limit)
+		}
+
+		c.hostConfig.MemorySwap = limit
+		return nil
+	}
+}
+
+// CPUs limits the number of CPUs the container can use, such as 1.5.
+func CPUs(cpus float64) ContainerOption {
+	return func(c *Container) error {
+		if cpus < 0 {
+			return fmt.Errorf("cpus %f is negative", cpus)
+		}
+
+		c.hostConfig.NanoCPUs = int64(cpus * 1e9)
+		return nil
+	}
+}
+
+// CPUQuota limits the CPU time of the container to quota microseconds per period microseconds.
+func CPUQuota(quota, period int64) ContainerOption {
+	return func(c *Container) error {
+		if quota < 0 || period < 0 {
+			return fmt.Errorf("cpu quota %d, and period %d must not be negative", quota, period)
+		}
+
+		c.hostConfig.CPUQuota = quota
+		c.hostConfig.CPUPeriod = period
+		return nil
+	}
+}
+
+// CPUShares sets the CPU weight of the container relative to other containers.
+func CPUShares(shares int64) ContainerOption {
+	return func(c *Container) error {
+		if shares < 0 {
+			return fmt.Errorf("cpu shares %d is negative", shares)
+		}
+
+		c.hostConfig.CPUShares = shares
+		return nil
+	}
+}
+
+// CPUSet sets the CPUs the container is allowed to run on, such as `0-2` or `0,1`.
+func CPUSet(cpus string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.CpusetCpus = cpus
+		return nil
+	}
+}
+
+// PidsLimit limits the number of processes in the container.
+func PidsLimit(limit int64) ContainerOption {
+	return func(c *Container) error {
+		if limit <= 0 {
+			return fmt.Errorf("pids limit %d must be positive", limit)
+		}
+
+		c.hostConfig.PidsLimit = &limit
+		return nil
+	}
+}
+
+// Ulimit sets the soft and hard limit of the given resource, such as `nofile`.
+func Ulimit(name string, soft, hard int64) ContainerOption {
+	return func(c *Container) error {
+		if soft > hard {
+			return fmt.Errorf("ulimit `%s` soft limit %d exceeds hard limit %d", name, soft, hard)
+		}
+
+		c.hostConfig.Ulimits = append(c.hostConfig.Ulimits, &units.Ulimit{
+			Name: name,
+			Soft: soft,
+			Hard: hard,
+		})
+		return nil
+	}
+}
+
+// ShmSize sets the size of /dev/shm in the container, in bytes.
+func ShmSize(size int64) ContainerOption {
+	return func(c *Container) error {
+		if size < 0 {
+			return fmt.Errorf("shm size %d is negative", size)
+		}
+
+		c.hostConfig.ShmSize = size
+		return nil
+	}
+}
+
+// BlkioWeight sets the block IO weight of the container relative to other containers, between 10 and 1000.
+func BlkioWeight(weight uint16) ContainerOption {
+	return func(c *Container) error {
+		if weight < 10 || weight > 1000 {
+			return fmt.Errorf("blkio weight %d is not between 10 and 1000", weight)
+		}
+
+		c.hostConfig.BlkioWeight = weight
+		return nil
+	}
+}
+
+/**************** Container Network Options ****************/
+
+// NetworkMode sets the network the container is attached to: `none`, `host`, `bridge`, or the name of a network.
+func NetworkMode(mode string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.NetworkMode = container.NetworkMode(mode)
+		return nil
+	}
+}
+
+// Port publishes the container port, such as `5432/tcp`, the protocol defaults to tcp.
+// Without a binding the port is published on a random host port, bindings are given as `[ip:]hostPort`, such as `127.0.0.1:15432`.
+// Use Container.HostPort to discover the host address of a published port.
+func Port(port string, bindings ...string) ContainerOption {
+	return func(c *Container) error {
+		proto, number := nat.SplitProtoPort(port)
+		containerPort, err := nat.NewPort(proto, number)
+		if err != nil {
+			return fmt.Errorf("parsing port `%s` failed with: %w", port, err)
+		}
+
+		portBindings := []nat.PortBinding{{}}
+		if len(bindings) > 0 {
+			portBindings = make([]nat.PortBinding, len(bindings))
+			for idx, binding := range bindings {
+				if portBindings[idx], err = parsePortBinding(binding); err != nil {
+					return err
+				}
+			}
+		}
+
+		if c.config.ExposedPorts == nil {
+			c.config.ExposedPorts = make(nat.PortSet)
+		}
+		if c.hostConfig.PortBindings == nil {
+			c.hostConfig.PortBindings = make(nat.PortMap)
+		}
+
+		c.config.ExposedPorts[containerPort] = struct{}{}
+		c.hostConfig.PortBindings[containerPort] = append(c.hostConfig.PortBindings[containerPort], portBindings...)
+		return nil
+	}
+}
+
+// NetworkAliases sets names the container can be reached by on its network, in addition to its own name.
+// Aliases require the container to be attached to a named network.
+func NetworkAliases(aliases ...string) ContainerOption {
+	return func(c *Container) error {
+		c.aliases = append(c.aliases, aliases...)
+		return nil
+	}
+}
+
+// ExtraHost adds an entry mapping host to ip to the container's /etc/hosts.
+func ExtraHost(host, ip string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.ExtraHosts = append(c.hostConfig.ExtraHosts, host+":"+ip)
+		return nil
+	}
+}
+
+// DNS sets the DNS servers of the container.
+func DNS(servers ...string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.DNS = append(c.hostConfig.DNS, servers...)
+		return nil
+	}
+}
+
+// DNSSearch sets the DNS search domains of the container.
+func DNSSearch(domains ...string) ContainerOption {
+	return func(c *Container) error {
+		c.hostConfig.DNSSearch = append(c.hostConfig.DNSSearch, domains...)
+		return nil
+	}
+}
+
+// Hostname sets the hostname of the container.
+func Hostname(hostname string) ContainerOption {
+	return func(c *Container) error {
+		c.config.Hostname = hostname
+		return nil
+	}
+}
+
+func parsePortBinding(binding string) (nat.PortBinding, error) {
+	if !strings.Contains(binding, ":") {
+		return nat.PortBinding{HostPort: binding}, nil
+	}
+
+	ip, port, err := net.SplitHostPort(binding)
+	if err != nil {
+		return nat.PortBinding{}, fmt.Errorf("parsing port binding `%s` failed with: %w", binding, err)
+	}
+
+	return nat.PortBinding{HostIP: ip, HostPort: port}, nil
+}
+
 func toEnvFormat(key, value string) string {
 	return key + "=" + value
 }
+
+/**************** Commit Options ****************/
+
+// CommitOption is a function to set configuration used when committing a container to an image.
+type CommitOption func(*types.ContainerCommitOptions) error
+
+// CommitConfig sets container configuration overrides applied to the committed image.
+// Fields left empty are inherited from the container.
+func CommitConfig(config *container.Config) CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		if config == nil {
+			return errors.New("commit config is nil")
+		}
+
+		labels := commitLabels(o)
+		override := *config
+		override.Labels = nil
+		o.Config = &override
+		for key, value := range config.Labels {
+			CommitLabel(key, value)(o)
+		}
+		for key, value := range labels {
+			CommitLabel(key, value)(o)
+		}
+
+		return nil
+	}
+}
+
+// CommitLabel sets a label on the committed image.
+func CommitLabel(key, value string) CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		if o.Config == nil {
+			o.Config = &container.Config{}
+		}
+		if o.Config.Labels == nil {
+			o.Config.Labels = make(map[string]string)
+		}
+
+		o.Config.Labels[key] = value
+		return nil
+	}
+}
+
+// CommitLabels sets multiple labels on the committed image.
+func CommitLabels(labels map[string]string) CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		for key, value := range labels {
+			CommitLabel(key, value)(o)
+		}
+		return nil
+	}
+}
+
+// CommitChanges applies Dockerfile instructions, such as `CMD` or `ENV`, to the committed image.
+func CommitChanges(changes ...string) CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		o.Changes = append(o.Changes, changes...)
+		return nil
+	}
+}
+
+// CommitAuthor sets the author of the committed image.
+func CommitAuthor(author string) CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		o.Author = author
+		return nil
+	}
+}
+
+// CommitMessage sets the commit message of the committed image.
+func CommitMessage(message string) CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		o.Comment = message
+		return nil
+	}
+}
+
+// CommitPause pauses the container while it is being committed.
+func CommitPause() CommitOption {
+	return func(o *types.ContainerCommitOptions) error {
+		o.Pause = true
+		return nil
+	}
+}
+
+func commitLabels(o *types.ContainerCommitOptions) map[string]string {
+	if o.Config == nil {
+		return nil
+	}
+
+	return o.Config.Labels
+}
+
+/**************** Network Options ****************/
+
+// NetworkOption is a function to set configuration to the Network object.
+type NetworkOption func(*Network) error
+
+// NetworkDriver sets the driver of the network, defaults to `bridge`.
+func NetworkDriver(driver string) NetworkOption {
+	return func(n *Network) error {
+		n.create.Driver = driver
+		return nil
+	}
+}
+
+// NetworkInternal restricts external access to the network.
+func NetworkInternal() NetworkOption {
+	return func(n *Network) error {
+		n.create.Internal = true
+		return nil
+	}
+}
+
+// NetworkSubnet sets the subnet, in CIDR format, and optionally the gateway of the network.
+func NetworkSubnet(subnet, gateway string) NetworkOption {
+	return func(n *Network) error {
+		if n.create.IPAM == nil {
+			n.create.IPAM = &network.IPAM{}
+		}
+
+		n.create.IPAM.Config = append(n.create.IPAM.Config, network.IPAMConfig{
+			Subnet:  subnet,
+			Gateway: gateway,
+		})
+		return nil
+	}
+}
+
+// NetworkLabel sets a label on the network.
+func NetworkLabel(key, value string) NetworkOption {
+	return func(n *Network) error {
+		if n.create.Labels == nil {
+			n.create.Labels = make(map[string]string)
+		}
+
+		n.create.Labels[key] = value
+		return nil
+	}
+}
+
+/**************** Volume Options ****************/
+
+// VolumeOption is a function to set configuration to the NamedVolume object.
+type VolumeOption func(*NamedVolume) error
+
+// VolumeDriver sets the driver of the volume, and its options.
+func VolumeDriver(driver string, options map[string]string) VolumeOption {
+	return func(v *NamedVolume) error {
+		v.driver = driver
+		v.driverOpts = options
+		return nil
+	}
+}
+
+// VolumeLabel sets a label on the volume.
+func VolumeLabel(key, value string) VolumeOption {
+	return func(v *NamedVolume) error {
+		if v.labels == nil {
+			v.labels = make(map[string]string)
+		}
+
+		v.labels[key] = value
+		return nil
+	}
+}
+
+/**************** Exec Options ****************/
+
+// ExecOption is a function to set configuration to a command run inside a container.
+type ExecOption func(*execution) error
+
+// ExecStdin sets the reader streamed into the command's standard input, which is closed once the reader reaches EOF.
+func ExecStdin(reader io.Reader) ExecOption {
+	return func(e *execution) error {
+		if reader == nil {
+			return errors.New("stdin reader is nil")
+		}
+
+		e.stdin = reader
+		return nil
+	}
+}
+
+// ExecVariable sets an environment variable for the command.
+func ExecVariable(key, value string) ExecOption {
+	return func(e *execution) error {
+		e.config.Env = append(e.config.Env, toEnvFormat(key, value))
+		return nil
+	}
+}
+
+// ExecWorkDir sets the working directory of the command.
+func ExecWorkDir(workDir string) ExecOption {
+	return func(e *execution) error {
+		e.config.WorkingDir = workDir
+		return nil
+	}
+}
+
+// ExecUser sets the user, and optionally group, the command runs as, such as `nobody` or `1000:1000`.
+func ExecUser(user string) ExecOption {
+	return func(e *execution) error {
+		e.config.User = user
+		return nil
+	}
+}
+
+/**************** File Options ****************/
+
+// FileOption is a function to set the permissions and ownership of files copied into a container.
+type FileOption func(*fileAttrs)
+
+// FileMode sets the permissions of copied files.
+func FileMode(mode fs.FileMode) FileOption {
+	return func(a *fileAttrs) {
+		a.mode = &mode
+	}
+}
+
+// DirMode sets the permissions of copied directories.
+func DirMode(mode fs.FileMode) FileOption {
+	return func(a *fileAttrs) {
+		a.dirMode = &mode
+	}
+}
+
+// Owner sets the uid and gid owning copied files and directories.
+func Owner(uid, gid int) FileOption {
+	return func(a *fileAttrs) {
+		a.uid = &uid
+		a.gid = &gid
+	}
+}
+
+func fileOptions(options []FileOption) *fileAttrs {
+	if len(options) == 0 {
+		return nil
+	}
+
+	attrs := new(fileAttrs)
+	for _, opt := range options {
+		opt(attrs)
+	}
+
+	return attrs
+}
+
+/**************** Log Options ****************/
+
+// LogOption is a function to set which container logs are returned by Logs.
+type LogOption func(*types.ContainerLogsOptions) error
+
+// LogFollow keeps streaming the logs as the container produces them, until it stops.
+func LogFollow() LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		o.Follow = true
+		return nil
+	}
+}
+
+// LogTimestamps prefixes every log line with its RFC3339Nano timestamp, and a space.
+func LogTimestamps() LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		o.Timestamps = true
+		return nil
+	}
+}
+
+// LogSince only returns the logs produced at or after t.
+func LogSince(t time.Time) LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		o.Since = logTimestamp(t)
+		return nil
+	}
+}
+
+// LogUntil only returns the logs produced before t.
+func LogUntil(t time.Time) LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		o.Until = logTimestamp(t)
+		return nil
+	}
+}
+
+// LogTail only returns the last lines of the logs produced so far, followed by new lines when following.
+func LogTail(lines int) LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		if lines < 0 {
+			return fmt.Errorf("invalid tail of %d lines", lines)
+		}
+
+		o.Tail = strconv.Itoa(lines)
+		return nil
+	}
+}
+
+// LogStdout sets whether the standard Out logs are returned, which they are by default.
+func LogStdout(show bool) LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		o.ShowStdout = show
+		return nil
+	}
+}
+
+// LogStderr sets whether the standard Error logs are returned, which they are by default.
+func LogStderr(show bool) LogOption {
+	return func(o *types.ContainerLogsOptions) error {
+		o.ShowStderr = show
+		return nil
+	}
+}
+
+// logTimestamp formats t as the `seconds.nanoseconds` timestamp accepted by the docker host.
+func logTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
@@ -0,0 +1,99 @@
+package containers
+
+import (
+	"github.com/distribution/reference"
+)
+
+// Reference is an image reference, parsed and normalized to its fully qualified form.
+// Image IDs are accepted as references holding only a digest.
+type Reference struct {
+	named  reference.Named
+	digest string
+}
+
+// ParseReference parses and normalizes the given image name, such that `node`, `node:latest`,
+// and `docker.io/library/node:latest` all refer to the same image. A *ReferenceError is returned for invalid references.
+func ParseReference(name string) (*Reference, error) {
+	parsed, err := reference.ParseAnyReference(name)
+	if err != nil {
+		return nil, &ReferenceError{Reference: name, Err: err}
+	}
+
+	ref := new(Reference)
+	if digested, ok := parsed.(reference.Digested); ok {
+		ref.digest = digested.Digest().String()
+	}
+
+	if named, ok := parsed.(reference.Named); ok {
+		ref.named = named
+		if len(ref.digest) == 0 {
+			ref.named = reference.TagNameOnly(named)
+		}
+	}
+
+	return ref, nil
+}
+
+// Registry returns the registry domain of the reference, such as `docker.io`.
+func (r *Reference) Registry() string {
+	if r.named == nil {
+		return ""
+	}
+
+	return reference.Domain(r.named)
+}
+
+// Repository returns the repository path of the reference within its registry, such as `library/node`.
+func (r *Reference) Repository() string {
+	if r.named == nil {
+		return ""
+	}
+
+	return reference.Path(r.named)
+}
+
+// Name returns the fully qualified repository name of the reference, such as `docker.io/library/node`.
+func (r *Reference) Name() string {
+	if r.named == nil {
+		return ""
+	}
+
+	return r.named.Name()
+}
+
+// Tag returns the tag of the reference, `latest` when neither a tag nor a digest was given.
+func (r *Reference) Tag() string {
+	if tagged, ok := r.named.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+
+	return ""
+}
+
+// Digest returns the digest of the reference, if any.
+func (r *Reference) Digest() string {
+	return r.digest
+}
+
+// Familiar returns the shortest form of the reference, as displayed by the docker cli.
+func (r *Reference) Familiar() string {
+	if r.named == nil {
+		return r.digest
+	}
+
+	return reference.FamiliarString(r.named)
+}
+
+// String returns the fully qualified form of the reference.
+func (r *Reference) String() string {
+	if r.named == nil {
+		return r.digest
+	}
+
+	return r.named.String()
+}
+
+// isID returns true if the reference is an image ID rather than a named reference.
+func (r *Reference) isID() bool {
+	return r.named == nil
+}
@@ -0,0 +1,110 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// rewrite replaces the prefix of fully qualified references.
+type rewrite struct {
+	prefix      string
+	replacement string
+}
+
+// candidates returns the references to pull the given reference from, in order, after applying the
+// client's rewrite rules and registry mirrors. The rewritten reference is always the last candidate.
+func (c *Client) candidates(ref *Reference) ([]*Reference, error) {
+	if ref.isID() {
+		return []*Reference{ref}, nil
+	}
+
+	name := ref.String()
+	for _, rule := range c.rewrites {
+		if strings.HasPrefix(name, rule.prefix) {
+			name = rule.replacement + strings.TrimPrefix(name, rule.prefix)
+			break
+		}
+	}
+
+	rewritten, err := ParseReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*Reference
+	for _, mirror := range c.mirrors[rewritten.Registry()] {
+		mirrored, err := ParseReference(mirror + strings.TrimPrefix(rewritten.String(), rewritten.Registry()))
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, mirrored)
+	}
+
+	return append(candidates, rewritten), nil
+}
+
+// hasImage checks the docker host client if the reference is known.
+func (c *Client) hasImage(ctx context.Context, ref *Reference) bool {
+	if ref.isID() {
+		_, _, err := c.ImageInspectWithRaw(ctx, ref.String())
+		return err == nil
+	}
+
+	res, err := c.ImageList(ctx, types.ImageListOptions{
+		Filters: NewFilter("reference", ref.Familiar()),
+	})
+
+	return err == nil && len(res) > 0
+}
+
+// retag tags an image pulled from a rewritten or mirrored candidate with the image reference, and untags the candidate
+// unless it was already on the docker host, so the image is known by the reference it was requested with.
+// References holding a digest cannot be tagged, so the image stays known by the candidate, which is recorded
+// as the resolved reference of the image.
+func (i *Image) retag(ctx context.Context, candidate *Reference) error {
+	if candidate.String() == i.ref.String() {
+		i.resolved = nil
+		return nil
+	}
+
+	if len(i.ref.Digest()) > 0 {
+		i.resolved = candidate
+		return nil
+	}
+
+	if err := i.client.ImageTag(ctx, candidate.String(), i.ref.String()); err != nil {
+		return errorImageTag(candidate.String(), err)
+	}
+
+	if known := i.resolved != nil && i.resolved.String() == candidate.String(); !known {
+		if _, err := i.client.ImageRemove(ctx, candidate.String(), types.ImageRemoveOptions{}); err != nil {
+			return errorImageTag(candidate.String(), err)
+		}
+	}
+
+	i.resolved = nil
+	return nil
+}
+
+// pullCandidates pulls the image from each candidate in order, until one succeeds.
+func (i *Image) pullCandidates(ctx context.Context) error {
+	candidates, err := i.client.candidates(i.ref)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, candidate := range candidates {
+		if err = i.pull(ctx, candidate); err == nil {
+			return i.retag(ctx, candidate)
+		}
+
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package containers
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// Source is a set of files copied into a container, created by MapSource, DirSource, FSSource, or TarSource.
+type Source interface {
+	// archive writes the files as a tarball, with names placed under prefix.
+	archive(w io.Writer, prefix string) error
+}
+
+// fileAttrs overrides the permissions and ownership of copied files.
+type fileAttrs struct {
+	mode    *fs.FileMode
+	dirMode *fs.FileMode
+	uid     *int
+	gid     *int
+}
+
+func (a *fileAttrs) apply(header *tar.Header) {
+	switch {
+	case header.Typeflag == tar.TypeDir && a.dirMode != nil:
+		header.Mode = int64(a.dirMode.Perm())
+	case header.Typeflag == tar.TypeReg && a.mode != nil:
+		header.Mode = int64(a.mode.Perm())
+	}
+
+	if a.uid != nil {
+		header.Uid = *a.uid
+		header.Uname = ""
+	}
+
+	if a.gid != nil {
+		header.Gid = *a.gid
+		header.Gname = ""
+	}
+}
+
+type mapSource struct {
+	files map[string][]byte
+	attrs *fileAttrs
+}
+
+func (s mapSource) archive(w io.Writer, prefix string) error {
+	return tarFiles(w, s.files, prefix, s.attrs)
+}
+
+type dirSource struct {
+	dir   string
+	attrs *fileAttrs
+}
+
+func (s dirSource) archive(w io.Writer, prefix string) error {
+	return tarDirectory(w, s.dir, prefix, s.attrs)
+}
+
+type fsSource struct {
+	fsys  fs.FS
+	attrs *fileAttrs
+}
+
+func (s fsSource) archive(w io.Writer, prefix string) error {
+	return tarFS(w, s.fsys, prefix, s.attrs)
+}
+
+type tarSource struct {
+	reader io.Reader
+}
+
+func (s tarSource) archive(w io.Writer, prefix string) error {
+	return prefixTar(w, s.reader, prefix)
+}
+
+// MapSource returns a Source of in-memory files, keyed by their path relative to the destination.
+// Files default to mode 0644, and their parent directories to 0755, owned by root.
+func MapSource(files map[string][]byte, options ...FileOption) Source {
+	return mapSource{files: files, attrs: fileOptions(options)}
+}
+
+// DirSource returns a Source of the contents of the local directory dir, with their permissions and ownership unless overridden.
+func DirSource(dir string, options ...FileOption) Source {
+	return dirSource{dir: dir, attrs: fileOptions(options)}
+}
+
+// FSSource returns a Source of the contents of the file system, such as an embed.FS.
+func FSSource(fsys fs.FS, options ...FileOption) Source {
+	return fsSource{fsys: fsys, attrs: fileOptions(options)}
+}
+
+// TarSource returns a Source of the entries of an uncompressed tarball, copied as is.
+func TarSource(tarball io.Reader) Source {
+	return tarSource{reader: tarball}
+}
+
+// CopyIn copies the files of the source into the container, under the directory dst which is created if needed.
+// It does not rely on bind mounts, so it works with remote docker hosts, and before the container is started.
+// The files are extracted at the closest existing parent of dst, so dst may be a writable mount of a read-only root filesystem.
+// A tmpfs mount is only mounted once the container starts, so files copied under it beforehand are hidden; use a volume instead.
+func (c *Container) CopyIn(ctx context.Context, dst string, src Source) error {
+	if src == nil {
+		return errorContainerCopyIn(c.id, dst, ErrorNilSource)
+	}
+
+	dst = path.Clean("/" + dst)
+	base, err := c.existingParent(ctx, dst)
+	if err != nil {
+		return errorContainerCopyIn(c.id, dst, err)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(src.archive(writer, strings.TrimPrefix(strings.TrimPrefix(dst, base), "/")))
+	}()
+	defer reader.Close()
+
+	if err = c.image.client.CopyToContainer(ctx, c.id, base, reader, types.CopyToContainerOptions{}); err != nil {
+		return errorContainerCopyIn(c.id, dst, err)
+	}
+
+	return nil
+}
+
+// existingParent returns dst, or its closest parent that exists in the container.
+func (c *Container) existingParent(ctx context.Context, dst string) (string, error) {
+	for dir := dst; ; dir = path.Dir(dir) {
+		_, err := c.image.client.ContainerStatPath(ctx, c.id, dir)
+		if err == nil {
+			return dir, nil
+		}
+
+		if !client.IsErrNotFound(err) || dir == "/" {
+			return "", err
+		}
+	}
+}
@@ -5,13 +5,16 @@ package tests
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
+	"path"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
 	ci "github.com/taubyte/go-simple-container"
 	"github.com/taubyte/go-simple-container/gc"
 )
@@ -280,3 +283,855 @@ func TestContainerParallel(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestContainerCommit(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	file, err := os.OpenFile(DockerTarBallPath, os.O_RDWR, 0444)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer file.Close()
+
+	image, err := cli.Image(ctx, testCustomImage, ci.Build(file))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "echo " + message + " > /snapshot"}),
+		ci.KeepContainer(),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	logs.Close()
+
+	committed, err := container.Commit(ctx, testCommitImage, ci.CommitLabel("test", "commit"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ImageRemove(ctx, committed.Name(), types.ImageRemoveOptions{Force: true})
+
+	snapshot, err := committed.Instantiate(ctx, ci.Command([]string{"cat", "/snapshot"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err = snapshot.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	if !strings.Contains(buf.String(), message) {
+		t.Error("Committed image does not contain the container's filesystem changes")
+	}
+}
+
+func TestContainerResources(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command(basicCommand),
+		ci.CPUs(0.5),
+		ci.PidsLimit(64),
+		ci.Ulimit("nofile", 1024, 2048),
+		ci.ShmSize(16*1024*1024),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	logs.Close()
+
+	container, err = image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "x=$(head -c 64m /dev/zero | tr '\\0' a); echo ${#x}"}),
+		ci.Memory(8*1024*1024),
+		ci.MemorySwap(8*1024*1024),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err = container.Run(ctx)
+	if !errors.Is(err, ci.ErrorOOMKilled) {
+		t.Errorf("Expected container to be OOM killed got: %v", err)
+	}
+	if logs != nil {
+		logs.Close()
+	}
+}
+
+func TestContainerMountTypes(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	_, err = image.Instantiate(ctx, ci.Volume(path.Join(VolumePath, "missing"), "/src"))
+	if !errors.Is(err, ci.ErrorContainerMount) {
+		t.Errorf("Expected missing bind source to fail got: %v", err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "cat /src/" + TestScript + "; touch /src/fail || echo read-only; echo " + message + " > /data/msg; df -k /tmp | tail -1"}),
+		ci.Volume(VolumePath, "/src", ci.ReadOnly()),
+		ci.MountVolume(testVolumeName, "/data"),
+		ci.Tmpfs("/tmp", ci.TmpfsSize(1024*1024), ci.TmpfsMode(01777)),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.VolumeRemove(ctx, testVolumeName, true)
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	out := buf.String()
+	if !strings.Contains(out, TestScriptMessage) || !strings.Contains(out, "read-only") || !strings.Contains(out, "tmpfs") {
+		t.Errorf("Unexpected mounts output: %s", out)
+		return
+	}
+
+	container, err = image.Instantiate(ctx, ci.Command([]string{"cat", "/data/msg"}), ci.MountVolume(testVolumeName, "/data"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err = container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf.Reset()
+	buf.ReadFrom(logs.Combined())
+	if !strings.Contains(buf.String(), message) {
+		t.Error("Named volume content was not persisted")
+	}
+}
+
+func TestContainerIdentity(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Name(testContainerName),
+		ci.Label("test", "identity"),
+		ci.User("nobody", "nogroup"),
+		ci.Entrypoint([]string{"/bin/sh", "-c"}),
+		ci.Command([]string{"id -un; hostname -f"}),
+		ci.Hostname("test-host"),
+		ci.Domainname("example.test"),
+		ci.StopSignal("SIGINT"),
+		ci.KeepContainer(),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	found, err := cli.Container(ctx, testContainerName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if found.ID() != container.ID() || found.Name() != testContainerName || found.Image().Reference().String() != image.Reference().String() {
+		t.Error("Container found by name does not match the instantiated container")
+		return
+	}
+
+	info, err := cli.ContainerInspect(ctx, testContainerName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if info.Config.Labels["test"] != "identity" || info.Config.StopSignal != "SIGINT" {
+		t.Error("Container labels and stop signal were not set")
+		return
+	}
+
+	logs, err := found.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	out := buf.String()
+	if !strings.Contains(out, "nobody") || !strings.Contains(out, "test-host") {
+		t.Errorf("Unexpected container identity: %s", out)
+		return
+	}
+
+	if _, err = cli.ContainerInspect(ctx, testContainerName); err != nil {
+		t.Errorf("Expected container found by name to be kept after run: %v", err)
+	}
+
+}
+
+func TestContainerHardened(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	profile := `{"defaultAction":"SCMP_ACT_ALLOW","syscalls":[{"names":["mkdir","mkdirat"],"action":"SCMP_ACT_ERRNO"}]}`
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "touch /rootfs || echo read-only; touch /tmp/ok && echo writable; grep CapEff /proc/self/status; mkdir /tmp/dir || echo seccomp"}),
+		ci.Hardened(),
+		ci.SeccompProfile(profile),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	out := buf.String()
+	for _, expected := range []string{"read-only", "writable", "0000000000000000", "seccomp"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected hardened container output to contain `%s` got: %s", expected, out)
+		}
+	}
+}
+
+func TestContainerHardenedOverrides(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	profile := path.Join(t.TempDir(), "profile")
+	if err = os.WriteFile(profile, []byte("#include <tunables/global>\n\nprofile test-profile flags=(attach_disconnected) {\n}\n"), 0644); err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"true"}),
+		ci.Hardened(),
+		ci.ReadOnlyRootfs(false),
+		ci.NoNewPrivileges(false),
+		ci.PidsLimit(64),
+		ci.CapAdd("CHOWN"),
+		ci.Tmpfs("/tmp", ci.TmpfsSize(1<<20)),
+		ci.AppArmorProfile(profile),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ContainerRemove(ctx, container.ID(), types.ContainerRemoveOptions{Force: true})
+
+	info, err := cli.ContainerInspect(ctx, container.ID())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	hostConfig := info.HostConfig
+	if hostConfig.ReadonlyRootfs {
+		t.Error("Expected ReadOnlyRootfs(false) to override Hardened")
+	}
+
+	if hostConfig.PidsLimit == nil || *hostConfig.PidsLimit != 64 {
+		t.Errorf("Expected pids limit 64 got %v", hostConfig.PidsLimit)
+	}
+
+	if len(hostConfig.CapDrop) != 1 || hostConfig.CapDrop[0] != "ALL" || len(hostConfig.CapAdd) != 1 || hostConfig.CapAdd[0] != "CHOWN" {
+		t.Errorf("Expected capabilities to drop ALL and add CHOWN got drop %v add %v", hostConfig.CapDrop, hostConfig.CapAdd)
+	}
+
+	securityOpts := strings.Join(hostConfig.SecurityOpt, " ")
+	if !strings.Contains(securityOpts, "no-new-privileges=false") || strings.Contains(securityOpts, "no-new-privileges=true") {
+		t.Errorf("Expected NoNewPrivileges(false) to override Hardened got %v", hostConfig.SecurityOpt)
+	}
+
+	if !strings.Contains(securityOpts, "apparmor=test-profile") {
+		t.Errorf("Expected apparmor profile name read from file got %v", hostConfig.SecurityOpt)
+	}
+
+	var tmpMounts int
+	for _, m := range hostConfig.Mounts {
+		if m.Target == "/tmp" {
+			tmpMounts++
+			if m.TmpfsOptions == nil || m.TmpfsOptions.SizeBytes != 1<<20 {
+				t.Errorf("Expected later /tmp tmpfs to replace Hardened one got %+v", m)
+			}
+		}
+	}
+	if tmpMounts != 1 {
+		t.Errorf("Expected one /tmp mount got %d", tmpMounts)
+	}
+
+	if _, err = image.Instantiate(ctx, ci.AppArmorProfile(path.Join(t.TempDir(), "missing"))); err == nil {
+		t.Error("Expected missing apparmor profile file to fail")
+	}
+}
+
+func TestContainerStdin(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-s"}),
+		ci.Stdin(strings.NewReader("echo "+message+"\nwc -c\n")),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	if !strings.Contains(buf.String(), message) {
+		t.Errorf("Expected script from stdin to run got: %s", buf.String())
+	}
+}
+
+func TestContainerTTY(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "tty; stty size; echo " + message + " >&2"}),
+		ci.TTY(120, 40),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	out := buf.String()
+	if !strings.Contains(out, "/dev/pts/") || !strings.Contains(out, "40 120") || !strings.Contains(out, message) {
+		t.Errorf("Unexpected TTY output: %q", out)
+	}
+}
+
+func TestContainerExec(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(ctx, ci.Command([]string{"sleep", "60"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ContainerRemove(ctx, container.ID(), types.ContainerRemoveOptions{Force: true})
+
+	if err = cli.ContainerStart(ctx, container.ID(), types.ContainerStartOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	result, err := container.Exec(
+		ctx,
+		[]string{"/bin/sh", "-c", "cat; echo $" + testEnv + "; pwd; id -un; echo " + message + " >&2"},
+		ci.ExecStdin(strings.NewReader(TestScriptMessage+"\n")),
+		ci.ExecVariable(testEnv, testVal),
+		ci.ExecWorkDir("/tmp"),
+		ci.ExecUser("nobody"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := strings.Join([]string{TestScriptMessage, testVal, "/tmp", "nobody", ""}, "\n")
+	if string(result.Stdout) != expected || strings.TrimSpace(string(result.Stderr)) != message {
+		t.Errorf("Unexpected exec output `%q`, and `%q`", result.Stdout, result.Stderr)
+		return
+	}
+
+	result, err = container.Exec(ctx, []string{"/bin/sh", "-c", "exit 3"})
+	if !errors.Is(err, ci.ErrorExitCode) || result == nil || result.ExitCode != 3 {
+		t.Errorf("Expected exit code 3 got: %v", err)
+		return
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err = container.Exec(execCtx, []string{"sleep", "3600"})
+	if !errors.Is(err, ci.ErrorContainerExec) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected exec deadline error got: %v", err)
+		return
+	}
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Exec returned %v after its deadline", elapsed)
+	}
+}
+
+func TestContainerFiles(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	dir := t.TempDir()
+	if err = os.WriteFile(path.Join(dir, "local.txt"), []byte(testVal), 0600); err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Files("/data", ci.MapSource(map[string][]byte{"config/nested/app.conf": []byte(message)}, ci.FileMode(0640), ci.DirMode(0750), ci.Owner(1000, 1000))),
+		ci.Files("/data/dir", ci.DirSource(dir)),
+		ci.Command([]string{"/bin/sh", "-c", "cat /data/config/nested/app.conf /data/dir/local.txt; stat -c '%a %u:%g' /data/config/nested/app.conf /data/config/nested /data/config"}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(logs.Combined())
+	logs.Close()
+
+	expected := message + testVal + "640 1000:1000\n750 1000:1000\n750 1000:1000\n"
+	if buf.String() != expected {
+		t.Errorf("Expected `%q` got `%q`", expected, buf.String())
+	}
+}
+
+func TestContainerArtifacts(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "mkdir -p /out/bin && echo " + message + " > /out/bin/app && echo " + testVal + " > /report.txt"}),
+		ci.CollectArtifacts("/out", "/report.txt"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	artifacts := logs.Artifacts
+	if len(artifacts) != 2 {
+		t.Errorf("Expected 2 artifacts got %d", len(artifacts))
+		return
+	}
+
+	dir := t.TempDir()
+	for _, artifact := range artifacts {
+		if err = artifact.Extract(dir); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	for name, expected := range map[string]string{"bin/app": message, "report.txt": testVal} {
+		data, err := os.ReadFile(path.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if strings.TrimSpace(string(data)) != expected {
+			t.Errorf("Expected `%s` in %s got `%s`", expected, name, data)
+			return
+		}
+	}
+
+	if err = logs.Close(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err = artifacts[0].Tar(); err == nil {
+		t.Error("Expected spooled artifact to be removed once the result is closed")
+	}
+}
+
+func TestContainerRunResult(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(ctx, ci.Command([]string{"/bin/sh", "-c", "echo " + message + "; echo " + testVal + " >&2; sleep 1; exit 7"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	result, err := container.Run(ctx)
+	var exitErr *ci.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 7 || !errors.Is(err, ci.ErrorExitCode) {
+		t.Errorf("Expected exit code 7 got: %v", err)
+		return
+	}
+
+	if result == nil || result.ExitCode != 7 || result.OOMKilled || result.ContainerID != container.ID() {
+		t.Errorf("Unexpected result %+v", result)
+		return
+	}
+
+	if result.Duration < time.Second || !result.FinishedAt.After(result.StartedAt) {
+		t.Errorf("Unexpected timing started:%v finished:%v duration:%v", result.StartedAt, result.FinishedAt, result.Duration)
+		return
+	}
+
+	stdOut, stdErr, err := result.Output()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if strings.TrimSpace(string(stdOut)) != message || strings.TrimSpace(string(stdErr)) != testVal {
+		t.Errorf("Unexpected output `%q`, and `%q`", stdOut, stdErr)
+	}
+}
+
+func TestContainerCleanupOnCancel(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(ctx, ci.Command([]string{"sleep", "60"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err = container.Run(runCtx); err == nil {
+		t.Error("Expected run to fail once its context is done")
+		return
+	}
+
+	if _, err = cli.ContainerInspect(ctx, container.ID()); !client.IsErrNotFound(err) {
+		t.Errorf("Expected container to be removed got: %v", err)
+	}
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(ctx, ci.Command([]string{"sleep", "60"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectStatus := func(status string) bool {
+		state, err := container.State(ctx)
+		if err != nil {
+			t.Error(err)
+			return false
+		}
+
+		if state.Status != status {
+			t.Errorf("Expected status `%s` got `%s`", status, state.Status)
+			return false
+		}
+
+		return true
+	}
+
+	if !expectStatus("running") {
+		return
+	}
+
+	if err = container.Pause(ctx); err != nil || !expectStatus("paused") {
+		t.Error(err)
+		return
+	}
+
+	if err = container.Unpause(ctx); err != nil || !expectStatus("running") {
+		t.Error(err)
+		return
+	}
+
+	if err = container.Restart(ctx, time.Second); err != nil || !expectStatus("running") {
+		t.Error(err)
+		return
+	}
+
+	if err = container.Kill(ctx, "SIGKILL"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err = container.Wait(ctx); err != nil || !expectStatus("exited") {
+		t.Error(err)
+		return
+	}
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err = container.Stop(ctx, time.Second); err != nil || !expectStatus("exited") {
+		t.Error(err)
+	}
+}
+
+func TestContainerFilesReadOnlyRootfs(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	volume := testVolumeName + "-files"
+	defer cli.VolumeRemove(ctx, volume, true)
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Hardened(),
+		ci.MountVolume(volume, "/data"),
+		ci.Files("/data/config", ci.MapSource(map[string][]byte{"app.conf": []byte(message)})),
+		ci.Command([]string{"cat", "/data/config/app.conf"}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(logs.Combined())
+	logs.Close()
+
+	if buf.String() != message {
+		t.Errorf("Expected `%s` got `%s`", message, buf.String())
+	}
+}
@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ci "github.com/taubyte/go-simple-container"
+)
+
+// timedWriter records the output written to it, and when it was first written to.
+type timedWriter struct {
+	lock  sync.Mutex
+	buf   bytes.Buffer
+	first time.Time
+}
+
+func (w *timedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.first.IsZero() {
+		w.first = time.Now()
+	}
+
+	return w.buf.Write(p)
+}
+
+func TestContainerStreamOutput(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var stdOut, stdErr timedWriter
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "echo " + message + "; echo " + testVal + " >&2; sleep 2"}),
+		ci.StreamOutput(&stdOut, &stdErr),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	result, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer result.Close()
+
+	if strings.TrimSpace(stdOut.buf.String()) != message || strings.TrimSpace(stdErr.buf.String()) != testVal {
+		t.Errorf("Unexpected streamed output `%q`, and `%q`", stdOut.buf.String(), stdErr.buf.String())
+		return
+	}
+
+	if !stdOut.first.Before(result.FinishedAt.Add(-time.Second)) {
+		t.Errorf("Expected output before the container exited, got it at %v for exit at %v", stdOut.first, result.FinishedAt)
+	}
+}
+
+func TestContainerLogs(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(ctx, ci.Command([]string{"/bin/sh", "-c", "for i in 1 2 3 4 5; do echo line $i; sleep 0.2; done"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Logs(ctx, ci.LogFollow(), ci.LogTimestamps())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(logs.Combined())
+	logs.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Errorf("Expected 5 followed lines got `%q`", buf.String())
+		return
+	}
+
+	for i, line := range lines {
+		timestamp, text, _ := strings.Cut(line, " ")
+		if _, err = time.Parse(time.RFC3339Nano, timestamp); err != nil || text != "line "+string(rune('1'+i)) {
+			t.Errorf("Unexpected line `%s`", line)
+			return
+		}
+	}
+
+	logs, err = container.Logs(ctx, ci.LogTail(2))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	buf.Reset()
+	buf.ReadFrom(logs.Combined())
+	logs.Close()
+
+	if buf.String() != "line 4\nline 5\n" {
+		t.Errorf("Unexpected tail `%q`", buf.String())
+	}
+}
+
+func (w *timedWriter) String() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.buf.String()
+}
+
+func TestContainerRestartStreamOutput(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var stdOut timedWriter
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "echo " + message + "; sleep 60"}),
+		ci.StreamOutput(&stdOut, nil),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	waitOutput := func(count int) bool {
+		for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(100 * time.Millisecond) {
+			if strings.Count(stdOut.String(), message) >= count {
+				return true
+			}
+		}
+
+		t.Errorf("Expected %d streamed messages got `%q`", count, stdOut.String())
+		return false
+	}
+
+	if !waitOutput(1) {
+		return
+	}
+
+	if err = container.Restart(ctx, 0); err != nil {
+		t.Error(err)
+		return
+	}
+
+	waitOutput(2)
+}
+
+func TestContainerStdinReleased(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the writer is never closed, so the reader never reaches EOF, and the reader is owned by the test
+	reader, writer := io.Pipe()
+	stdin := &closeRecorder{Reader: reader}
+	container, err := image.Instantiate(
+		ctx,
+		ci.Stdin(stdin),
+		ci.StreamOutput(io.Discard, io.Discard),
+		ci.Command([]string{"true"}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err = container.Run(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// once the container stopped, streaming ends on its first failed write, so later writes are never read
+	var writes atomic.Int32
+	go func() {
+		for {
+			if _, err := writer.Write([]byte(message)); err != nil {
+				return
+			}
+			writes.Add(1)
+		}
+	}()
+	defer writer.Close()
+
+	time.Sleep(5 * time.Second)
+	if n := writes.Load(); n > 1 {
+		t.Errorf("Expected stdin streaming to stop once the container stopped, got %d writes read", n)
+	}
+
+	if stdin.closed.Load() {
+		t.Error("Expected stdin reader owned by the caller to be left open")
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed.Store(true)
+	return nil
+}
@@ -0,0 +1,232 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	ci "github.com/taubyte/go-simple-container"
+)
+
+func TestMuxedReadCloserRaw(t *testing.T) {
+	raw := "\x1b[32m" + message + "\x1b[0m\r\n"
+	logs := ci.NewMuxedReadCloser(io.NopCloser(bytes.NewBufferString(raw)))
+
+	out, err := io.ReadAll(logs.Combined())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if string(out) != raw {
+		t.Errorf("Expected raw output `%q` got `%q`", raw, out)
+		return
+	}
+
+	stdOut, stdErr := ci.NewMuxedReadCloser(io.NopCloser(bytes.NewBufferString(raw))).Separated()
+	out, _ = io.ReadAll(stdOut)
+	errOut, _ := io.ReadAll(stdErr)
+	if string(out) != raw || len(errOut) != 0 {
+		t.Errorf("Expected raw output on standard out got `%q`, and `%q`", out, errOut)
+	}
+}
+
+func TestMuxedReadCloserMultiplexed(t *testing.T) {
+	muxed := new(bytes.Buffer)
+	stdcopy.NewStdWriter(muxed, stdcopy.Stdout).Write([]byte(message))
+	stdcopy.NewStdWriter(muxed, stdcopy.Stderr).Write([]byte(testVal))
+
+	out, err := io.ReadAll(ci.NewMuxedReadCloser(io.NopCloser(bytes.NewReader(muxed.Bytes()))).Combined())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if string(out) != message+testVal {
+		t.Errorf("Unexpected combined output `%q`", out)
+	}
+}
+
+func TestMuxedReadCloserLines(t *testing.T) {
+	muxed := new(bytes.Buffer)
+	stdOut := stdcopy.NewStdWriter(muxed, stdcopy.Stdout)
+	stdErr := stdcopy.NewStdWriter(muxed, stdcopy.Stderr)
+	stdOut.Write([]byte("first\nsec"))
+	stdErr.Write([]byte(`{"level":"warn","msg":"` + message + `"}` + "\n"))
+	stdOut.Write([]byte("ond\r\n" + strings.Repeat("é", 5) + "\n"))
+	stdErr.Write([]byte("unterminated"))
+
+	defer func(size int) {
+		ci.MaxLogLineSize = size
+	}(ci.MaxLogLineSize)
+	ci.MaxLogLineSize = 4
+
+	logs := ci.NewMuxedReadCloser(io.NopCloser(bytes.NewReader(muxed.Bytes())))
+
+	var lines []ci.LogLine
+	for line := range logs.Lines(context.Background()) {
+		lines = append(lines, line)
+	}
+
+	if err := logs.Err(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []ci.LogLine{
+		{Stream: ci.StreamStdout, Text: "firs", Partial: true},
+		{Stream: ci.StreamStdout, Text: "t"},
+		{Stream: ci.StreamStderr, Text: `{"le`, Partial: true},
+	}
+	if len(lines) < len(expected) {
+		t.Errorf("Unexpected lines %+v", lines)
+		return
+	}
+
+	for i, line := range expected {
+		if lines[i] != line {
+			t.Errorf("Expected line %d to be %+v got %+v", i, line, lines[i])
+			return
+		}
+	}
+
+	ci.MaxLogLineSize = 1024
+	logs = ci.NewMuxedReadCloser(io.NopCloser(bytes.NewReader(muxed.Bytes())))
+
+	var texts []string
+	for line := range logs.Lines(context.Background()) {
+		texts = append(texts, line.Stream.String()+":"+line.Text)
+	}
+
+	expectedTexts := []string{
+		"stdout:first",
+		"stderr:" + `{"level":"warn","msg":"` + message + `"}`,
+		"stdout:second",
+		"stdout:" + strings.Repeat("é", 5),
+		"stderr:unterminated",
+	}
+	if strings.Join(texts, "\n") != strings.Join(expectedTexts, "\n") {
+		t.Errorf("Expected lines `%q` got `%q`", expectedTexts, texts)
+		return
+	}
+
+	var entry struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	if err := (ci.LogLine{Text: strings.TrimPrefix(expectedTexts[1], "stderr:")}).DecodeJSON(&entry); err != nil || entry.Level != "warn" || entry.Msg != message {
+		t.Errorf("Unexpected decoded line %+v: %v", entry, err)
+	}
+}
+
+func TestMuxedReadCloserLinesSplitRunes(t *testing.T) {
+	defer func(size int) {
+		ci.MaxLogLineSize = size
+	}(ci.MaxLogLineSize)
+	ci.MaxLogLineSize = 5
+
+	text := strings.Repeat("é", 7)
+	muxed := new(bytes.Buffer)
+	stdcopy.NewStdWriter(muxed, stdcopy.Stdout).Write([]byte(text + "\n"))
+
+	var joined string
+	for line := range ci.NewMuxedReadCloser(io.NopCloser(muxed)).Lines(context.Background()) {
+		if !utf8.ValidString(line.Text) {
+			t.Errorf("Line split inside a rune `%q`", line.Text)
+			return
+		}
+		joined += line.Text
+	}
+
+	if joined != text {
+		t.Errorf("Expected `%s` got `%s`", text, joined)
+	}
+}
+
+// failingReader returns its data, then fails with err.
+type failingReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestMuxedReadCloserSeparatedBuffered(t *testing.T) {
+	defer func(size int) {
+		ci.MaxLogBuffer = size
+	}(ci.MaxLogBuffer)
+	ci.MaxLogBuffer = 16
+
+	outData := strings.Repeat(message, 100)
+	errData := strings.Repeat(testVal, 100)
+
+	muxed := new(bytes.Buffer)
+	stdOut := stdcopy.NewStdWriter(muxed, stdcopy.Stdout)
+	stdErr := stdcopy.NewStdWriter(muxed, stdcopy.Stderr)
+	for i := 0; i < 100; i++ {
+		stdOut.Write([]byte(message))
+		stdErr.Write([]byte(testVal))
+	}
+
+	outReader, errReader := ci.NewMuxedReadCloser(io.NopCloser(bytes.NewReader(muxed.Bytes()))).Separated()
+	errOut, err := io.ReadAll(errReader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	out, err := io.ReadAll(outReader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if string(out) != outData || string(errOut) != errData {
+		t.Errorf("Unexpected separated output of %d, and %d bytes", len(out), len(errOut))
+		return
+	}
+
+	outReader, errReader = ci.NewMuxedReadCloser(io.NopCloser(bytes.NewReader(muxed.Bytes()))).Separated()
+	errReader.Close()
+	if out, err = io.ReadAll(outReader); err != nil || string(out) != outData {
+		t.Errorf("Expected standard out with standard error unread got %d bytes: %v", len(out), err)
+	}
+	outReader.Close()
+}
+
+func TestMuxedReadCloserCopyError(t *testing.T) {
+	errRead := errors.New("connection reset")
+
+	muxed := new(bytes.Buffer)
+	stdcopy.NewStdWriter(muxed, stdcopy.Stdout).Write([]byte(message))
+	stdcopy.NewStdWriter(muxed, stdcopy.Stderr).Write([]byte(testVal))
+
+	logs := ci.NewMuxedReadCloser(io.NopCloser(&failingReader{data: bytes.NewReader(muxed.Bytes()), err: errRead}))
+	out, err := io.ReadAll(logs.Combined())
+	if !errors.Is(err, errRead) || string(out) != message+testVal {
+		t.Errorf("Expected combined output `%q` before the read error got `%q`: %v", message+testVal, out, err)
+		return
+	}
+
+	logs = ci.NewMuxedReadCloser(io.NopCloser(&failingReader{data: bytes.NewReader(muxed.Bytes()), err: errRead}))
+	outReader, errReader := logs.Separated()
+	if out, err = io.ReadAll(outReader); !errors.Is(err, errRead) || string(out) != message {
+		t.Errorf("Expected standard out `%q` before the read error got `%q`: %v", message, out, err)
+		return
+	}
+
+	if out, err = io.ReadAll(errReader); !errors.Is(err, errRead) || string(out) != testVal {
+		t.Errorf("Expected standard error `%q` before the read error got `%q`: %v", testVal, out, err)
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	ci "github.com/taubyte/go-simple-container"
+)
+
+func TestContainerNetwork(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	network, err := cli.Network(ctx, testNetwork, ci.NetworkLabel("test", "network"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer network.Remove(ctx)
+
+	if _, err = cli.Network(ctx, testNetwork, ci.NetworkLabel("test", "network")); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err = cli.Network(ctx, testNetwork, ci.NetworkLabel("test", "other")); !errors.Is(err, ci.ErrorNetworkExists) {
+		t.Errorf("Expected mismatching network error got: %v", err)
+		return
+	}
+
+	if _, err = cli.Network(ctx, testNetwork, ci.NetworkInternal()); !errors.Is(err, ci.ErrorNetworkExists) {
+		t.Errorf("Expected mismatching network error got: %v", err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "hostname; cat /etc/hosts; ping -c 1 -W 2 test-alias"}),
+		ci.NetworkMode(network.Name()),
+		ci.NetworkAliases("test-alias"),
+		ci.Hostname("test-host"),
+		ci.ExtraHost("example.test", "10.1.2.3"),
+		ci.DNSSearch("example.test"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	out := buf.String()
+	if !strings.Contains(out, "test-host") || !strings.Contains(out, "10.1.2.3") {
+		t.Errorf("Unexpected network configuration: %s", out)
+		return
+	}
+
+	container, err = image.Instantiate(ctx, ci.Command([]string{"ls", "/sys/class/net"}), ci.NetworkMode("none"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err = container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf.Reset()
+	buf.ReadFrom(logs.Combined())
+	if strings.TrimSpace(buf.String()) != "lo" {
+		t.Errorf("Expected only the loopback interface got: %s", buf.String())
+	}
+}
+
+func TestContainerHostPort(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "mkdir /www && echo " + message + " > /www/index.html && httpd -f -p 8080 -h /www"}),
+		ci.Port("8080/tcp", "127.0.0.1:"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ContainerRemove(ctx, container.ID(), types.ContainerRemoveOptions{Force: true})
+
+	if err = cli.ContainerStart(ctx, container.ID(), types.ContainerStartOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	addr, err := container.HostPort(ctx, "8080/tcp")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !strings.HasPrefix(addr, "127.0.0.1:") {
+		t.Errorf("Expected port to be bound on localhost got `%s`", addr)
+		return
+	}
+
+	var body []byte
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(100 * time.Millisecond) {
+		if res, err := http.Get("http://" + addr); err == nil {
+			body, _ = io.ReadAll(res.Body)
+			res.Body.Close()
+			break
+		}
+	}
+
+	if !strings.Contains(string(body), message) {
+		t.Errorf("Unexpected response `%s` from published port", body)
+	}
+
+	if _, err = container.HostPort(ctx, "9090/tcp"); !errors.Is(err, ci.ErrorPortNotPublished) {
+		t.Errorf("Expected unpublished port error got: %v", err)
+	}
+}
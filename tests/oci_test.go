@@ -0,0 +1,264 @@
+package tests
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
+	ci "github.com/taubyte/go-simple-container"
+	"github.com/taubyte/go-simple-container/oci"
+)
+
+func TestAssembleArchive(t *testing.T) {
+	builder, err := oci.New(
+		oci.Files(oci.File{Path: "/app/hello.sh", Data: []byte("echo " + message), Mode: 0755}),
+		oci.Command("/bin/sh", "/app/hello.sh"),
+		oci.Label("test", "assembly"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer builder.Close()
+
+	base := new(bytes.Buffer)
+	if err = builder.Write(base, testAssembledImage); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// assemble on top of the previous archive to check it is readable as a base
+	layered, err := oci.New(
+		oci.Base(base),
+		oci.Files(oci.File{Path: "etc/motd", Data: []byte(message)}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer layered.Close()
+
+	config := layered.Config()
+	if len(config.RootFS.DiffIDs) != 2 {
+		t.Errorf("Expected 2 layers got %d", len(config.RootFS.DiffIDs))
+		return
+	}
+
+	if config.Config.Labels["test"] != "assembly" || len(config.Config.Cmd) != 2 {
+		t.Error("Base image config was not inherited")
+		return
+	}
+
+	archive := new(bytes.Buffer)
+	if err = layered.Write(archive, testAssembledImage); err != nil {
+		t.Error(err)
+		return
+	}
+
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(archive)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		entries[header.Name], _ = io.ReadAll(tr)
+	}
+
+	for _, name := range []string{"oci-layout", "index.json", "manifest.json"} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("Expected archive to contain %s", name)
+			return
+		}
+	}
+
+	var manifests []struct {
+		Config   string
+		RepoTags []string
+		Layers   []string
+	}
+	if err = json.Unmarshal(entries["manifest.json"], &manifests); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(manifests) != 1 || len(manifests[0].Layers) != 2 || manifests[0].RepoTags[0] != testAssembledImage {
+		t.Errorf("Unexpected docker manifest %s", entries["manifest.json"])
+		return
+	}
+
+	for _, name := range append(manifests[0].Layers, manifests[0].Config) {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("Expected archive to contain blob %s", name)
+		}
+	}
+}
+
+func TestAssembleImage(t *testing.T) {
+	ci.ForceRebuild = true
+
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err = cli.Image(ctx, testBaseImage); err != nil {
+		t.Error(err)
+		return
+	}
+
+	base, err := cli.ImageSave(ctx, []string{testBaseImage})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer base.Close()
+
+	builder, err := oci.New(
+		oci.Base(base),
+		oci.Files(oci.File{Path: "/app/hello.sh", Data: []byte("echo " + message), Mode: 0755}),
+		oci.Command("/bin/sh", "/app/hello.sh"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer builder.Close()
+
+	image, err := cli.Image(ctx, testAssembledImage, ci.Assemble(builder))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ImageRemove(ctx, image.Name(), types.ImageRemoveOptions{Force: true})
+
+	container, err := image.Instantiate(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	if !strings.Contains(buf.String(), message) {
+		t.Error("Container output not the same as the given message")
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	builder, err := oci.New(
+		oci.Files(oci.File{Path: "/app/hello.sh", Data: []byte("echo " + message), Mode: 0755}),
+		oci.Command("/bin/sh", "/app/hello.sh"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer builder.Close()
+
+	dir := t.TempDir()
+	if err = builder.WriteLayout(dir, testAssembledImage); err != nil {
+		t.Error(err)
+		return
+	}
+
+	manifest, err := oci.ResolveManifest(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, desc := range append(manifest.Layers, manifest.Config) {
+		data, err := os.ReadFile(oci.BlobPath(dir, desc.Digest))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if digest.FromBytes(data) != desc.Digest {
+			t.Errorf("Blob %s does not match its digest", desc.Digest)
+			return
+		}
+	}
+
+	layout, err := oci.New(oci.Layout(dir))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer layout.Close()
+
+	if config := layout.Config(); len(config.RootFS.DiffIDs) != 1 || len(config.Config.Cmd) != 2 {
+		t.Error("Layout image does not match the written image")
+	}
+}
+
+func TestImageExportOCI(t *testing.T) {
+	ci.ForceRebuild = true
+
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	dir := t.TempDir()
+	if err = image.ExportOCI(ctx, dir); err != nil {
+		t.Error(err)
+		return
+	}
+
+	imported, err := cli.Image(ctx, testAssembledImage, ci.OCILayout(dir))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ImageRemove(ctx, imported.Name(), types.ImageRemoveOptions{Force: true})
+
+	container, err := imported.Instantiate(ctx, ci.Command(basicCommand))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	if !strings.Contains(buf.String(), message) {
+		t.Error("Container output not the same as the given message")
+	}
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ci "github.com/taubyte/go-simple-container"
+)
+
+func TestReferenceNormalization(t *testing.T) {
+	for _, name := range []string{"node", "node:latest", "library/node", "docker.io/library/node:latest"} {
+		ref, err := ci.ParseReference(name)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if ref.String() != "docker.io/library/node:latest" || ref.Familiar() != "node:latest" {
+			t.Errorf("Reference `%s` normalized to `%s`", name, ref.String())
+		}
+
+		if ref.Registry() != "docker.io" || ref.Repository() != "library/node" || ref.Tag() != "latest" || len(ref.Digest()) > 0 {
+			t.Errorf("Unexpected accessors for reference `%s`", name)
+		}
+	}
+
+	digest := "sha256:" + strings.Repeat("a", 64)
+	ref, err := ci.ParseReference("localhost:5000/taubyte/test:v1@" + digest)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if ref.Registry() != "localhost:5000" || ref.Repository() != "taubyte/test" || ref.Tag() != "v1" || ref.Digest() != digest {
+		t.Errorf("Unexpected accessors for reference `%s`", ref)
+	}
+}
+
+func TestReferenceInvalid(t *testing.T) {
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, name := range []string{"", "Node", "node:", "-node", "node@sha256:short"} {
+		_, err = cli.Image(context.Background(), name)
+
+		var refErr *ci.ReferenceError
+		if !errors.As(err, &refErr) || !errors.Is(err, ci.ErrorInvalidReference) {
+			t.Errorf("Expected a reference error for `%s` got: %v", name, err)
+		}
+	}
+}
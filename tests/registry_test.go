@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	ci "github.com/taubyte/go-simple-container"
+)
+
+func TestRegistryMirrors(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, port := range []string{testRegistryPort, testMirrorPort} {
+		id, err := startRegistry(ctx, cli, port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer cli.ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+	}
+
+	// seed only the mirror with the base image
+	if _, err = cli.Image(ctx, testBaseImage); err != nil {
+		t.Error(err)
+		return
+	}
+
+	seeded := "localhost:" + testMirrorPort + "/library/busybox:latest"
+	if err = cli.ImageTag(ctx, testBaseImage, seeded); err != nil {
+		t.Error(err)
+		return
+	}
+
+	push, err := cli.ImagePush(ctx, seeded, types.ImagePushOptions{RegistryAuth: "e30="})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	io.Copy(io.Discard, push)
+	push.Close()
+
+	if _, err = cli.ImageRemove(ctx, seeded, types.ImageRemoveOptions{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	mirrored, err := ci.New(ci.Mirrors("localhost:"+testRegistryPort, "localhost:"+testMirrorPort))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	original := "localhost:" + testRegistryPort + "/library/busybox"
+	image, err := mirrored.Image(ctx, original)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ImageRemove(ctx, original, types.ImageRemoveOptions{})
+
+	if image.Name() != original {
+		t.Errorf("Expected image name `%s` got `%s`", original, image.Name())
+		return
+	}
+
+	if err = runMessage(ctx, image); err != nil {
+		t.Error(err)
+		return
+	}
+
+	rewritten, err := ci.New(ci.Rewrite("docker.io/taubyte/mirrored", "localhost:"+testMirrorPort+"/library/busybox"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err = rewritten.Image(ctx, "taubyte/mirrored")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ImageRemove(ctx, "taubyte/mirrored", types.ImageRemoveOptions{})
+
+	if err = runMessage(ctx, image); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the first mirror is missing the image, so the second one is pulled from
+	fallback, err := ci.New(ci.Mirrors(testUnknownRegistry, "localhost:"+testRegistryPort, "localhost:"+testMirrorPort))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	requested := testUnknownRegistry + "/library/busybox:latest"
+	image, err = fallback.Image(ctx, requested)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer cli.ImageRemove(ctx, requested, types.ImageRemoveOptions{})
+
+	if image.Name() != requested {
+		t.Errorf("Expected image name `%s` got `%s`", requested, image.Name())
+		return
+	}
+
+	if err = runMessage(ctx, image); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// digest references cannot be retagged, so the image is run by the mirror's reference but keeps its requested name
+	digest, err := manifestDigest("localhost:"+testMirrorPort, "library/busybox", "latest")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err = fallback.Image(ctx, testUnknownRegistry+"/library/busybox@"+digest)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	candidate := "localhost:" + testMirrorPort + "/library/busybox@" + digest
+	defer cli.ImageRemove(ctx, candidate, types.ImageRemoveOptions{})
+
+	if requested := testUnknownRegistry + "/library/busybox@" + digest; image.Name() != requested {
+		t.Errorf("Expected image name `%s` got `%s`", requested, image.Name())
+		return
+	}
+
+	if err = runMessage(ctx, image); err != nil {
+		t.Error(err)
+	}
+}
+
+// manifestDigest returns the digest of the manifest of repository:tag served by the registry.
+func manifestDigest(registry, repository, tag string) (string, error) {
+	req, err := http.NewRequest(http.MethodHead, "http://"+registry+"/v2/"+repository+"/manifests/"+tag, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", strings.Join([]string{
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}, ", "))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+
+	digest := res.Header.Get("Docker-Content-Digest")
+	if res.StatusCode != http.StatusOK || len(digest) == 0 {
+		return "", fmt.Errorf("getting manifest digest of %s:%s failed with status %d", repository, tag, res.StatusCode)
+	}
+
+	return digest, nil
+}
+
+// startRegistry runs a registry:2 container published on the given localhost port, and waits for it to serve.
+func startRegistry(ctx context.Context, cli *ci.Client, port string) (string, error) {
+	if _, err := cli.Image(ctx, testRegistryImage); err != nil {
+		return "", err
+	}
+
+	resp, err := cli.ContainerCreate(ctx, &container.Config{
+		Image:        testRegistryImage,
+		ExposedPorts: nat.PortSet{"5000/tcp": {}},
+	}, &container.HostConfig{
+		PortBindings: nat.PortMap{"5000/tcp": {{HostIP: "127.0.0.1", HostPort: port}}},
+	}, nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	if err = cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return resp.ID, err
+	}
+
+	for start := time.Now(); time.Since(start) < 30*time.Second; time.Sleep(200 * time.Millisecond) {
+		if res, err := http.Get("http://localhost:" + port + "/v2/"); err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return resp.ID, nil
+			}
+		}
+	}
+
+	return resp.ID, fmt.Errorf("registry on port %s did not start", port)
+}
+
+// runMessage runs the basic command in a container of the given image, and checks its output.
+func runMessage(ctx context.Context, image *ci.Image) error {
+	container, err := image.Instantiate(ctx, ci.Command(basicCommand))
+	if err != nil {
+		return err
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(logs.Combined())
+	if !strings.Contains(buf.String(), message) {
+		return fmt.Errorf("container output `%s` does not contain `%s`", buf.String(), message)
+	}
+
+	return nil
+}
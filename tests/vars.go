@@ -17,15 +17,25 @@ const (
 
 // Test Variables
 var (
-	message           = "testing message"
-	basicCommand      = []string{"echo", message}
-	testScriptCommand = []string{"/bin/sh", "/src/" + TestScript}
-	TestScriptMessage = "HELLO WORLD"
-	testVarsCommand   = []string{"/bin/sh", "/src/" + TestVarScript, "$" + testEnv}
-	testEnv           = "TEST"
-	testVal           = "Value42"
-	testCustomImage   = "taubyte/test:test2"
-	testVolume        = "volume"
+	message             = "testing message"
+	basicCommand        = []string{"echo", message}
+	testScriptCommand   = []string{"/bin/sh", "/src/" + TestScript}
+	TestScriptMessage   = "HELLO WORLD"
+	testVarsCommand     = []string{"/bin/sh", "/src/" + TestVarScript, "$" + testEnv}
+	testEnv             = "TEST"
+	testVal             = "Value42"
+	testCustomImage     = "taubyte/test:test2"
+	testCommitImage     = "taubyte/test:commit"
+	testAssembledImage  = "taubyte/test:assembled"
+	testBaseImage       = "busybox:latest"
+	testRegistryImage   = "registry:2"
+	testRegistryPort    = "5000"
+	testMirrorPort      = "5001"
+	testUnknownRegistry = "registry.invalid"
+	testNetwork         = "taubyte-test-network"
+	testVolumeName      = "taubyte-test-volume"
+	testContainerName   = "taubyte-test-container"
+	testVolume          = "volume"
 )
 
 var (
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	ci "github.com/taubyte/go-simple-container"
+)
+
+func TestNamedVolume(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	volume, err := cli.Volume(ctx, testVolumeName, ci.VolumeLabel("test", "volume"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer volume.Remove(ctx, true)
+
+	if _, err = cli.Volume(ctx, testVolumeName); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err = cli.Volume(ctx, testVolumeName, ci.VolumeLabel("test", "other")); !errors.Is(err, ci.ErrorVolumeExists) {
+		t.Errorf("Expected mismatching volume error got: %v", err)
+		return
+	}
+
+	volumes, err := cli.Volumes(ctx, "test=volume")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(volumes) != 1 || volumes[0].Name() != testVolumeName {
+		t.Errorf("Expected to list volume %s by label", testVolumeName)
+		return
+	}
+
+	if err = volume.ImportDir(ctx, VolumePath); err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "sh /data/" + TestScript + " > /data/out"}),
+		ci.MountVolume(volume.Name(), "/data"),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	logs, err := container.Run(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	logs.Close()
+
+	dir := t.TempDir()
+	if err = volume.ExportDir(ctx, dir); err != nil {
+		t.Error(err)
+		return
+	}
+
+	script, err := os.ReadFile(path.Join(dir, TestScript))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected, _ := os.ReadFile(ScriptPath)
+	if !bytes.Equal(script, expected) {
+		t.Error("Exported script does not match the imported script")
+		return
+	}
+
+	out, err := os.ReadFile(path.Join(dir, "out"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !bytes.Contains(out, []byte(TestScriptMessage)) {
+		t.Errorf("Unexpected container output in volume: %s", out)
+	}
+}
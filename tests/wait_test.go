@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ci "github.com/taubyte/go-simple-container"
+)
+
+func TestContainerWaitStrategies(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Port("8080/tcp", "127.0.0.1:"),
+		ci.Command([]string{"/bin/sh", "-c", "sleep 1; mkdir -p /www && echo ok > /www/index.html && echo service ready && httpd -f -p 8080 -h /www"}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = container.WaitReady(ctx, ci.WithDeadline(30*time.Second, ci.ForAll(
+		ci.ForLog("^service ready$"),
+		ci.ForPort("8080/tcp"),
+		ci.ForHTTP("8080/tcp", "/index.html", http.StatusOK),
+		ci.ForAny(ci.ForExec("test", "-f", "/www/index.html"), ci.ForLog("never")),
+	)))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = container.WaitReady(ctx, ci.WithDeadline(time.Second, ci.ForLog("never")))
+	if !errors.Is(err, ci.ErrorContainerNotReady) || !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "service ready") {
+		t.Errorf("Expected deadline failure with recent logs got: %v", err)
+		return
+	}
+
+	if err = container.WaitReady(ctx, ci.ForHealthy()); !errors.Is(err, ci.ErrorNoHealthCheck) {
+		t.Errorf("Expected no health check got: %v", err)
+		return
+	}
+
+	for _, strategy := range []ci.WaitStrategy{ci.ForAny(), ci.ForAll()} {
+		if err = container.WaitReady(ctx, strategy); !errors.Is(err, ci.ErrorNoWaitStrategy) {
+			t.Errorf("Expected no wait strategy error got: %v", err)
+			return
+		}
+	}
+}
+
+func TestContainerWaitExited(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(ctx, ci.Command([]string{"/bin/sh", "-c", "echo " + message + "; exit 1"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, strategy := range []ci.WaitStrategy{ci.ForPort("8080/tcp"), ci.ForLog("never")} {
+		err = container.WaitReady(ctx, strategy)
+		if !errors.Is(err, ci.ErrorContainerExited) || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected exited failure with recent logs got: %v", err)
+			return
+		}
+	}
+}
+
+func TestContainerHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	cli, err := ci.New()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	image, err := cli.Image(ctx, testBaseImage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	container, err := image.Instantiate(
+		ctx,
+		ci.Command([]string{"/bin/sh", "-c", "sleep 1; touch /tmp/healthy; sleep 60"}),
+		ci.HealthCheck([]string{"CMD-SHELL", "test -f /tmp/healthy && echo " + message}, 200*time.Millisecond, time.Second, 3, 0),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer container.Cleanup(ctx)
+
+	if err = container.Start(ctx); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err = container.WaitReady(ctx, ci.WithDeadline(30*time.Second, ci.ForHealthy())); err != nil {
+		t.Error(err)
+		return
+	}
+
+	health, err := container.Health(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if health.Status != "healthy" || len(health.Probes) == 0 {
+		t.Errorf("Unexpected health %+v", health)
+		return
+	}
+
+	last := health.Probes[len(health.Probes)-1]
+	if last.ExitCode != 0 || strings.TrimSpace(last.Output) != message {
+		t.Errorf("Unexpected probe %+v", last)
+	}
+}
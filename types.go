@@ -2,44 +2,123 @@ package containers
 
 import (
 	"io"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/taubyte/go-simple-container/oci"
 )
 
 var (
 	ForceRebuild = false
+
+	// CleanupTimeout bounds the removal of a container by Cleanup, which does not follow the cancellation of its context.
+	CleanupTimeout = 30 * time.Second
 )
 
 // MuxedReadCloser wraps the Read/Close methods for muxed logs.
 type MuxedReadCloser struct {
-	reader io.ReadCloser
+	reader     io.ReadCloser
+	tty        bool
+	timestamps bool
+	err        error
+}
+
+// RunResult is the outcome of Run. It embeds the container logs, which must be closed.
+type RunResult struct {
+	*MuxedReadCloser
+
+	ContainerID string
+	ExitCode    int
+	OOMKilled   bool
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Duration    time.Duration
+
+	// Artifacts are the paths collected by CollectArtifacts, in the order given, removed by Close.
+	Artifacts []*Artifact
 }
 
 // Client wraps the methods of the docker Client.
 type Client struct {
 	*client.Client
-}
-
-// volume defines the source and target to be volumed in the docker container.
-type volume struct {
-	source string
-	target string
+	rewrites    []rewrite
+	mirrors     map[string][]string
+	helperImage string
 }
 
 // Container wraps the methods of the docker container.
 type Container struct {
-	image   *Image
-	id      string
-	cmd     []string
-	shell   []string
-	volumes []volume
-	env     []string
-	workDir string
+	image      *Image
+	id         string
+	name       string
+	cmd        []string
+	shell      []string
+	mounts     []mount.Mount
+	env        []string
+	workDir    string
+	keep       bool
+	config     container.Config
+	hostConfig container.HostConfig
+	aliases    []string
+	stdin      io.Reader
+	tty        bool
+	files      []copyIn
+	artifacts  []string
+	stdout     io.Writer
+	stderr     io.Writer
+	streamed   <-chan error
+	// stdinStreamed is set once the Stdin reader was attached, so it is not streamed again on restart.
+	stdinStreamed bool
+	// release closes the connection attached by Start for the Stdin reader.
+	release func()
+}
+
+// copyIn defines files to be copied into the container before it is started.
+type copyIn struct {
+	dst string
+	src Source
 }
 
 // Image wraps the methods of the docker image.
 type Image struct {
 	client       *Client
 	image        string
+	ref          *Reference
+	resolved     *Reference
 	buildTarball io.Reader
+	assembly     *oci.Builder
+	layout       string
+}
+
+// Network wraps the methods of the docker network.
+type Network struct {
+	client *Client
+	id     string
+	name   string
+	create types.NetworkCreate
+}
+
+// NamedVolume wraps the methods of the docker volume.
+type NamedVolume struct {
+	client     *Client
+	name       string
+	driver     string
+	driverOpts map[string]string
+	labels     map[string]string
+}
+
+// execution holds the configuration of a command run inside a container.
+type execution struct {
+	config types.ExecConfig
+	stdin  io.Reader
+}
+
+// ExecResult holds the output and exit code of a command run inside a container.
+type ExecResult struct {
+	ExitCode int
+	Stdout   []byte
+	Stderr   []byte
 }
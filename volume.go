@@ -0,0 +1,207 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// volumeMountPath is where helper containers mount the volume they populate or extract.
+const volumeMountPath = "/volume"
+
+// Volume creates a named docker volume, or returns the existing volume of that name.
+// An existing volume must match the driver, driver options and labels given by the options.
+// Containers mount it with the MountVolume option.
+func (c *Client) Volume(ctx context.Context, name string, options ...VolumeOption) (*NamedVolume, error) {
+	v := &NamedVolume{
+		client: c,
+		name:   name,
+	}
+
+	for _, opt := range options {
+		if err := opt(v); err != nil {
+			return nil, errorVolumeOptions(name, err)
+		}
+	}
+
+	created, err := c.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       name,
+		Driver:     v.driver,
+		DriverOpts: v.driverOpts,
+		Labels:     v.labels,
+	})
+	if err != nil {
+		return nil, errorVolumeCreate(name, err)
+	}
+
+	if err = v.matches(created); err != nil {
+		return nil, errorVolumeExists(name, err)
+	}
+	v.driver = created.Driver
+	v.labels = created.Labels
+
+	return v, nil
+}
+
+// matches returns an error if the volume returned by the docker host differs from the options of the volume,
+// as is the case when a volume of that name already existed.
+func (v *NamedVolume) matches(existing volume.Volume) error {
+	if len(v.driver) > 0 && existing.Driver != v.driver {
+		return fmt.Errorf("driver is `%s`, expected `%s`", existing.Driver, v.driver)
+	}
+
+	if err := checkSubset("driver option", v.driverOpts, existing.Options); err != nil {
+		return err
+	}
+
+	return checkSubset("label", v.labels, existing.Labels)
+}
+
+// Volumes lists the named volumes matching all of the given labels, given as `key` or `key=value`.
+func (c *Client) Volumes(ctx context.Context, labels ...string) ([]*NamedVolume, error) {
+	filter := filters.NewArgs()
+	for _, label := range labels {
+		filter.Add("label", label)
+	}
+
+	res, err := c.VolumeList(ctx, volume.ListOptions{Filters: filter})
+	if err != nil {
+		return nil, errorVolumeList(err)
+	}
+
+	volumes := make([]*NamedVolume, len(res.Volumes))
+	for idx, v := range res.Volumes {
+		volumes[idx] = &NamedVolume{
+			client: c,
+			name:   v.Name,
+			driver: v.Driver,
+			labels: v.Labels,
+		}
+	}
+
+	return volumes, nil
+}
+
+// Inspect returns the docker host's information on the volume.
+func (v *NamedVolume) Inspect(ctx context.Context) (volume.Volume, error) {
+	info, err := v.client.VolumeInspect(ctx, v.name)
+	if err != nil {
+		return info, errorVolumeInspect(v.name, err)
+	}
+
+	return info, nil
+}
+
+// Remove removes the volume from the docker host client, force removes it even if it is in use.
+func (v *NamedVolume) Remove(ctx context.Context, force bool) error {
+	if err := v.client.VolumeRemove(ctx, v.name, force); err != nil {
+		return errorVolumeRemove(v.name, err)
+	}
+
+	return nil
+}
+
+// Import extracts the tarball into the volume. It does not rely on bind mounts, so it works with remote docker hosts.
+func (v *NamedVolume) Import(ctx context.Context, tarball io.Reader) error {
+	id, err := v.helper(ctx)
+	if err != nil {
+		return errorVolumeImport(v.name, err)
+	}
+	defer v.client.ContainerRemove(context.WithoutCancel(ctx), id, types.ContainerRemoveOptions{Force: true})
+
+	if err = v.client.CopyToContainer(ctx, id, volumeMountPath, tarball, types.CopyToContainerOptions{}); err != nil {
+		return errorVolumeImport(v.name, err)
+	}
+
+	return nil
+}
+
+// ImportDir copies the contents of the local directory dir into the volume.
+func (v *NamedVolume) ImportDir(ctx context.Context, dir string) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(tarDirectory(writer, dir, "", nil))
+	}()
+	defer reader.Close()
+
+	return v.Import(ctx, reader)
+}
+
+// Export returns the contents of the volume as a tarball, with names relative to the root of the volume.
+func (v *NamedVolume) Export(ctx context.Context) (io.ReadCloser, error) {
+	id, err := v.helper(ctx)
+	if err != nil {
+		return nil, errorVolumeExport(v.name, err)
+	}
+
+	content, _, err := v.client.CopyFromContainer(ctx, id, volumeMountPath)
+	if err != nil {
+		v.client.ContainerRemove(context.WithoutCancel(ctx), id, types.ContainerRemoveOptions{Force: true})
+		return nil, errorVolumeExport(v.name, err)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		defer v.client.ContainerRemove(context.WithoutCancel(ctx), id, types.ContainerRemoveOptions{Force: true})
+		defer content.Close()
+		writer.CloseWithError(rebaseTar(writer, content, volumeMountPath))
+	}()
+
+	return reader, nil
+}
+
+// ExportDir extracts the contents of the volume into the local directory dir.
+func (v *NamedVolume) ExportDir(ctx context.Context, dir string) error {
+	content, err := v.Export(ctx)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if err = untar(content, dir, ""); err != nil {
+		return errorVolumeExport(v.name, err)
+	}
+
+	return nil
+}
+
+// helper creates, without starting, a container of the client's helper image with the volume mounted,
+// for copying files in and out of the volume.
+func (v *NamedVolume) helper(ctx context.Context) (string, error) {
+	image, err := v.client.Image(ctx, v.client.helper())
+	if err != nil && image == nil {
+		return "", err
+	}
+
+	resp, err := v.client.ContainerCreate(ctx, &container.Config{
+		Image: image.local().String(),
+		Cmd:   []string{"true"},
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{{
+			Type:   mount.TypeVolume,
+			Source: v.name,
+			Target: volumeMountPath,
+		}},
+	}, nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// Name returns the name of the volume.
+func (v *NamedVolume) Name() string {
+	return v.name
+}
+
+// Labels returns the labels of the volume.
+func (v *NamedVolume) Labels() map[string]string {
+	return v.labels
+}
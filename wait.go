@@ -0,0 +1,290 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// WaitStrategy decides when a started container is ready to be used, see Container.WaitReady.
+type WaitStrategy interface {
+	// WaitUntilReady blocks until the container is ready, ctx is done, or the container can no longer become ready.
+	WaitUntilReady(ctx context.Context, c *Container) error
+}
+
+// WaitStrategyFunc adapts a function into a WaitStrategy.
+type WaitStrategyFunc func(ctx context.Context, c *Container) error
+
+func (f WaitStrategyFunc) WaitUntilReady(ctx context.Context, c *Container) error {
+	return f(ctx, c)
+}
+
+// waitPollInterval is the interval readiness checks are retried at.
+var waitPollInterval = 100 * time.Millisecond
+
+const (
+	// waitLogTail is the number of log lines included in readiness failures.
+	waitLogTail = 50
+
+	// waitDialTimeout bounds a single connection attempt of the port and HTTP strategies.
+	waitDialTimeout = time.Second
+)
+
+// WaitReady blocks until the strategy reports the started container ready.
+// Failures include the most recent lines of the container logs.
+func (c *Container) WaitReady(ctx context.Context, strategy WaitStrategy) error {
+	if err := strategy.WaitUntilReady(ctx, c); err != nil {
+		return errorContainerNotReady(c.id, c.image.image, err, c.recentLogs(ctx))
+	}
+
+	return nil
+}
+
+// recentLogs returns the last lines of the container logs, or an empty string if they cannot be read.
+func (c *Container) recentLogs(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CleanupTimeout)
+	defer cancel()
+
+	reader, err := c.Logs(ctx, LogTail(waitLogTail))
+	if err != nil {
+		return ""
+	}
+
+	logs, _ := io.ReadAll(reader.Combined())
+	return string(logs)
+}
+
+// permanentError marks a readiness failure that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// poll calls check every waitPollInterval until it succeeds, fails permanently, ctx is done, or the container exits.
+func (c *Container) poll(ctx context.Context, check func(ctx context.Context) error) error {
+	var permanent *permanentError
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if state, stateErr := c.State(ctx); stateErr == nil && (state.Status == "exited" || state.Status == "dead") {
+			if err = check(ctx); err == nil {
+				return nil
+			}
+
+			return fmt.Errorf("%w with exit code %d, last attempt: %s", ErrorContainerExited, state.ExitCode, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w, last attempt: %s", ctx.Err(), err)
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// ForLog is ready once a line of the container logs, stdout or stderr, matches the regular expression pattern.
+// The logs are followed from the start, so each line is only matched once.
+func ForLog(pattern string) WaitStrategy {
+	re, compileErr := regexp.Compile(pattern)
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		if compileErr != nil {
+			return compileErr
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		logs, err := c.Logs(ctx, LogFollow())
+		if err != nil {
+			return err
+		}
+
+		for line := range logs.Lines(ctx) {
+			if re.MatchString(line.Text) {
+				return nil
+			}
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%w, no log line matches `%s`", ctxErr, pattern)
+		}
+
+		if err = logs.Err(); err != nil {
+			return err
+		}
+
+		state, err := c.State(ctx)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w with exit code %d, no log line matches `%s`", ErrorContainerExited, state.ExitCode, pattern)
+	})
+}
+
+// ForPort is ready once the published container port, such as `8080/tcp`, accepts TCP connections from the host.
+func ForPort(port string) WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		return c.poll(ctx, func(ctx context.Context) error {
+			addr, err := c.HostPort(ctx, port)
+			if err != nil {
+				return err
+			}
+
+			dialer := net.Dialer{Timeout: waitDialTimeout}
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+
+			return conn.Close()
+		})
+	})
+}
+
+// ForHTTP is ready once a GET request of path, on the published container port, returns the status code.
+func ForHTTP(port, path string, status int) WaitStrategy {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	client := &http.Client{Timeout: waitDialTimeout}
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		return c.poll(ctx, func(ctx context.Context) error {
+			addr, err := c.HostPort(ctx, port)
+			if err != nil {
+				return err
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+path, nil)
+			if err != nil {
+				return &permanentError{err}
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			io.Copy(io.Discard, resp.Body)
+
+			if resp.StatusCode != status {
+				return fmt.Errorf("GET `%s` returned status %d, expected %d", path, resp.StatusCode, status)
+			}
+
+			return nil
+		})
+	})
+}
+
+// ForHealthy is ready once the docker health status of the container is `healthy`.
+// The image, or the HealthCheck option, must define a health check.
+func ForHealthy() WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		return c.poll(ctx, func(ctx context.Context) error {
+			health, err := c.Health(ctx)
+			if errors.Is(err, ErrorNoHealthCheck) {
+				return &permanentError{err}
+			}
+			if err != nil {
+				return err
+			}
+
+			if health.Status != types.Healthy {
+				return fmt.Errorf("health status is `%s`", health.Status)
+			}
+
+			return nil
+		})
+	})
+}
+
+// ForExec is ready once the command, run inside the container, exits with code 0.
+func ForExec(cmd ...string) WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		return c.poll(ctx, func(ctx context.Context) error {
+			_, err := c.Exec(ctx, cmd)
+			return err
+		})
+	})
+}
+
+// ForAll is ready once all strategies are, checked in order. It fails without strategies.
+func ForAll(strategies ...WaitStrategy) WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		if len(strategies) == 0 {
+			return ErrorNoWaitStrategy
+		}
+
+		for _, strategy := range strategies {
+			if err := strategy.WaitUntilReady(ctx, c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ForAny is ready once any of the strategies is, checked concurrently. It fails without strategies.
+func ForAny(strategies ...WaitStrategy) WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		if len(strategies) == 0 {
+			return ErrorNoWaitStrategy
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan error, len(strategies))
+		for _, strategy := range strategies {
+			go func(strategy WaitStrategy) {
+				results <- strategy.WaitUntilReady(ctx, c)
+			}(strategy)
+		}
+
+		errs := make([]error, 0, len(strategies))
+		for range strategies {
+			err := <-results
+			if err == nil {
+				return nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// WithDeadline bounds the strategy to timeout, failing if the container is not ready by then.
+func WithDeadline(timeout time.Duration, strategy WaitStrategy) WaitStrategy {
+	return WaitStrategyFunc(func(ctx context.Context, c *Container) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return strategy.WaitUntilReady(ctx, c)
+	})
+}